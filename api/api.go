@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -84,7 +85,19 @@ func (a *Api) GetUrl(path string) (string, error) {
 	return url.JoinPath(a.ApiUrl, path)
 }
 
-func (a *Api) GetAccessToken() (string, error) {
+// AuthHeaders returns the headers needed to call the MATTR API, fetching a
+// new access token if needed
+func (a *Api) AuthHeaders(ctx context.Context) (map[string]string, error) {
+	accessToken, err := a.GetAccessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{
+		"Authorization": "Bearer " + accessToken,
+	}, nil
+}
+
+func (a *Api) GetAccessToken(ctx context.Context) (string, error) {
 	timeStarted := time.Now().Unix()
 
 	var expireTolerance int64 = 15 // get a new token 15 seconds before it expires
@@ -117,7 +130,7 @@ func (a *Api) GetAccessToken() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	req, err := http.NewRequest("POST", auth_url, bytes.NewBuffer(req_body_json))
+	req, err := http.NewRequestWithContext(ctx, "POST", auth_url, bytes.NewBuffer(req_body_json))
 	if err != nil {
 		return "", err
 	}
@@ -187,7 +200,7 @@ func (a *Api) Request(method string, url string, body interface{}) (*http.Reques
 
 	log.Printf("Getting access token")
 
-	access_token, err := a.GetAccessToken()
+	access_token, err := a.GetAccessToken(req.Context())
 	if err != nil {
 		return nil, err
 	}
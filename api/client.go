@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -10,55 +11,56 @@ import (
 	"reflect"
 )
 
+// Client sends requests to the MATTR API. Every call takes a context whose
+// deadline bounds the whole request, so a hung call fails with the resource's
+// timeout rather than blocking the apply.
 type Client interface {
-	Post(url string, headers map[string]string, body interface{}) (interface{}, error)
-	Get(url string, headers map[string]string) (interface{}, error)
-	Put(url string, headers map[string]string, body interface{}) (interface{}, error)
-	Delete(url string, headers map[string]string) error
+	Post(ctx context.Context, url string, headers map[string]string, body interface{}) (*Response, error)
+	Get(ctx context.Context, url string, headers map[string]string) (*Response, error)
+	Put(ctx context.Context, url string, headers map[string]string, body interface{}) (*Response, error)
+	Delete(ctx context.Context, url string, headers map[string]string) error
+}
+
+// Response is a successful response from the MATTR API with its JSON body
+// decoded. Header names are canonicalised, e.g. "Etag".
+type Response struct {
+	StatusCode int
+	Headers    map[string]string
+	Body       interface{}
 }
 
 type HttpClient struct {
 }
 
-func (client *HttpClient) Post(url string, headers map[string]string, body interface{}) (interface{}, error) {
-	responseBod, err := send[interface{}]("POST", url, headers, &body)
-	if err != nil {
-		return nil, err
-	}
-	if responseBod == nil {
-		return nil, fmt.Errorf("Unable to load data for POST %s", url)
-	}
-	return *responseBod, err
+func (client *HttpClient) Post(ctx context.Context, url string, headers map[string]string, body interface{}) (*Response, error) {
+	return sendExpectingBody(ctx, "POST", url, headers, &body)
 }
 
-func (client *HttpClient) Get(url string, headers map[string]string) (interface{}, error) {
-	responseBod, err := send[interface{}]("GET", url, headers, nil)
-	if err != nil {
-		return nil, err
-	}
-	if responseBod == nil {
-		return nil, fmt.Errorf("Unable to load data for GET %s", url)
-	}
-	return *responseBod, err
+func (client *HttpClient) Get(ctx context.Context, url string, headers map[string]string) (*Response, error) {
+	return sendExpectingBody(ctx, "GET", url, headers, nil)
+}
+
+func (client *HttpClient) Put(ctx context.Context, url string, headers map[string]string, body interface{}) (*Response, error) {
+	return sendExpectingBody(ctx, "PUT", url, headers, &body)
 }
 
-func (client *HttpClient) Put(url string, headers map[string]string, body interface{}) (interface{}, error) {
-	responseBod, err := send[interface{}]("PUT", url, headers, &body)
+func (client *HttpClient) Delete(ctx context.Context, url string, headers map[string]string) error {
+	_, err := send(ctx, "DELETE", url, headers, nil)
+	return err
+}
+
+func sendExpectingBody(ctx context.Context, method string, url string, headers map[string]string, body *interface{}) (*Response, error) {
+	response, err := send(ctx, method, url, headers, body)
 	if err != nil {
 		return nil, err
 	}
-	if responseBod == nil {
-		return nil, fmt.Errorf("Unable to load data for PUT %s", url)
+	if response.Body == nil {
+		return nil, fmt.Errorf("Unable to load data for %s %s", method, url)
 	}
-	return *responseBod, err
-}
-
-func (client *HttpClient) Delete(url string, headers map[string]string) error {
-	_, err := send[interface{}]("DELETE", url, headers, nil)
-	return err
+	return response, nil
 }
 
-func send[T any](method string, url string, headers map[string]string, body *interface{}) (*T, error) {
+func send(ctx context.Context, method string, url string, headers map[string]string, body *interface{}) (*Response, error) {
 	client := http.DefaultClient
 
 	var bodyJson []byte
@@ -86,7 +88,7 @@ func send[T any](method string, url string, headers map[string]string, body *int
 
 	log.Printf("Uploading %d byte(s)", len(bodyJson))
 	bodyBuf := bytes.NewBuffer(bodyJson)
-	request, err := http.NewRequest(method, url, bodyBuf)
+	request, err := http.NewRequestWithContext(ctx, method, url, bodyBuf)
 	if err != nil {
 		return nil, err
 	}
@@ -96,6 +98,9 @@ func send[T any](method string, url string, headers map[string]string, body *int
 
 	resp, err := client.Do(request)
 	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("Timed out waiting for %s %s: %s", method, url, err)
+		}
 		return nil, err
 	}
 	if 400 <= resp.StatusCode && resp.StatusCode < 599 {
@@ -105,23 +110,35 @@ func send[T any](method string, url string, headers map[string]string, body *int
 		}
 		apiError, apiErrorErr := ParseError(responseBody)
 		if apiErrorErr != nil {
-			apiError.StatusCode = resp.StatusCode
-			apiError.Method = method
-			apiError.Url = url
-			return nil, fmt.Errorf("%s %s gave error status code: %d", method, url, resp.StatusCode)
+			log.Printf("Unable to parse error from %s %s: %s", method, url, apiErrorErr)
+			apiError = ApiError{}
 		}
+		apiError.StatusCode = resp.StatusCode
+		apiError.Method = method
+		apiError.Url = url
 		return nil, apiError
 	}
+
+	response := Response{
+		StatusCode: resp.StatusCode,
+		Headers:    make(map[string]string, len(resp.Header)),
+	}
+	for name := range resp.Header {
+		response.Headers[name] = resp.Header.Get(name)
+	}
+
 	if resp.StatusCode == 204 {
-		return nil, nil
+		resp.Body.Close()
+		return &response, nil
 	}
 
-	result, err := processResponse[T](resp)
+	result, err := processResponse[interface{}](resp)
 	if err != nil {
 		return nil, err
 	}
+	response.Body = *result
 
-	return result, nil
+	return &response, nil
 }
 
 func processResponse[T any](resp *http.Response) (*T, error) {
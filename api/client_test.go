@@ -0,0 +1,33 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHttpClientRespectsContextDeadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	client := HttpClient{}
+	started := time.Now()
+	_, err := client.Get(ctx, server.URL, map[string]string{})
+
+	if err == nil {
+		t.Fatal("Expected the request to time out")
+	}
+	if elapsed := time.Since(started); elapsed > time.Second {
+		t.Fatalf("Request took %s, the deadline was not enforced", elapsed)
+	}
+}
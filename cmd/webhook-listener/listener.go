@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"nz.antunovic/mattr-terraform-provider/webhook"
+)
+
+// Listener is an http.Handler that prints each webhook event it receives
+type Listener struct {
+	Verifier webhook.Verifier
+	// Insecure skips signature verification. Unverified events are printed
+	// but not recorded in Events.
+	Insecure bool
+	// Output receives the pretty-printed events
+	Output io.Writer
+	// Events, if set, receives each verified event as a line of JSON
+	Events io.Writer
+
+	mu sync.Mutex
+}
+
+// summaryFields are the payload fields worth printing for each event type,
+// before the full payload
+var summaryFields = map[string][]string{
+	"OidcIssuerCredentialIssued": {"credentialId", "issuerId", "userId", "credentialConfigurationId"},
+	"PresentationSubmitted":      {"presentationType", "challengeId", "verified", "holder", "claims"},
+}
+
+func (l *Listener) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Webhooks must be POSTed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var body []byte
+	var err error
+	if l.Insecure {
+		body, err = io.ReadAll(r.Body)
+	} else {
+		body, err = l.Verifier.Verify(r)
+	}
+	if err != nil {
+		log.Printf("Rejected request from %s: %s", r.RemoteAddr, err)
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	var event map[string]interface{}
+	if err := json.Unmarshal(body, &event); err != nil {
+		log.Printf("Rejected request from %s: body is not JSON: %s", r.RemoteAddr, err)
+		http.Error(w, "Body is not JSON", http.StatusBadRequest)
+		return
+	}
+
+	// requests can arrive concurrently, so keep their output apart
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.Output != nil {
+		fmt.Fprint(l.Output, formatEvent(event, !l.Insecure))
+	}
+	if l.Events != nil && !l.Insecure {
+		line, _ := json.Marshal(event)
+		if _, err := l.Events.Write(append(line, '\n')); err != nil {
+			log.Printf("Unable to record event: %s", err)
+		}
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// formatEvent prints a MATTR webhook delivery, which wraps an event of the
+// form {"id", "type", "timestamp", "payload"}
+func formatEvent(delivery map[string]interface{}, verified bool) string {
+	event, ok := delivery["event"].(map[string]interface{})
+	if !ok {
+		event = delivery
+	}
+	payload, _ := event["payload"].(map[string]interface{})
+	eventType, _ := event["type"].(string)
+	if len(eventType) == 0 {
+		eventType = "Unknown event"
+	}
+
+	var sb strings.Builder
+	status := "verified"
+	if !verified {
+		status = "NOT verified"
+	}
+	sb.WriteString(fmt.Sprintf("=== %s (%s)\n", eventType, status))
+
+	for _, field := range []string{"id", "timestamp"} {
+		if value, ok := event[field]; ok {
+			sb.WriteString(fmt.Sprintf("  %-26s %v\n", field+":", value))
+		}
+	}
+	if deliveryId, ok := delivery["deliveryId"]; ok {
+		sb.WriteString(fmt.Sprintf("  %-26s %v\n", "deliveryId:", deliveryId))
+	}
+
+	fields, known := summaryFields[eventType]
+	if !known {
+		// show whatever the payload has at the top level
+		for field := range payload {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+	}
+	for _, field := range fields {
+		value, ok := payload[field]
+		if !ok {
+			continue
+		}
+		if _, isString := value.(string); !isString {
+			formatted, _ := json.Marshal(value)
+			value = string(formatted)
+		}
+		sb.WriteString(fmt.Sprintf("  %-26s %v\n", field+":", value))
+	}
+
+	if known && payload != nil {
+		formatted, _ := json.MarshalIndent(payload, "  ", "  ")
+		sb.WriteString(fmt.Sprintf("  payload: %s\n", formatted))
+	}
+	sb.WriteString("\n")
+
+	return sb.String()
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"nz.antunovic/mattr-terraform-provider/webhook"
+)
+
+const testDelivery = `{
+	"deliveryId": "6a5b0e8c-0d1c-4e4c-9b7a-3f9b0f2c1e11",
+	"event": {
+		"id": "1c3f7e2a-8a0a-4c1b-9f3a-2a2c5b8d9e10",
+		"type": "OidcIssuerCredentialIssued",
+		"timestamp": "2024-01-02T03:04:05Z",
+		"payload": {
+			"credentialId": "urn:uuid:0b6f2c1e-3a4d-4e5f-8a9b-0c1d2e3f4a5b",
+			"issuerId": "983c0a86-204f-4431-9371-f5a22e506599"
+		}
+	}
+}`
+
+func signedRequest(t *testing.T, private ed25519.PrivateKey, body string) *http.Request {
+	r := httptest.NewRequest("POST", "http://localhost:8080/", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	digest := sha256.Sum256([]byte(body))
+	contentDigest := fmt.Sprintf("sha-256=:%s:", base64.StdEncoding.EncodeToString(digest[:]))
+	r.Header.Set("Content-Digest", contentDigest)
+
+	params := fmt.Sprintf(`("@method" "content-digest");created=%d;keyid="test-key";alg="ed25519"`, time.Now().Unix())
+	base := fmt.Sprintf("\"@method\": POST\n\"content-digest\": %s\n\"@signature-params\": %s", contentDigest, params)
+	signature := ed25519.Sign(private, []byte(base))
+
+	r.Header.Set("Signature-Input", "sig1="+params)
+	r.Header.Set("Signature", fmt.Sprintf("sig1=:%s:", base64.StdEncoding.EncodeToString(signature)))
+	return r
+}
+
+func testListener(t *testing.T) (*Listener, ed25519.PrivateKey, *bytes.Buffer, *bytes.Buffer) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := &bytes.Buffer{}
+	events := &bytes.Buffer{}
+	listener := &Listener{
+		Verifier: webhook.Verifier{Keys: &webhook.JWKS{Keys: []webhook.JWK{{
+			Kid: "test-key",
+			Kty: "OKP",
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(public),
+		}}}},
+		Output: output,
+		Events: events,
+	}
+	return listener, private, output, events
+}
+
+func TestListenerPrintsAndRecordsVerifiedEvents(t *testing.T) {
+	listener, private, output, events := testListener(t)
+
+	w := httptest.NewRecorder()
+	listener.ServeHTTP(w, signedRequest(t, private, testDelivery))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body)
+	}
+	if !strings.Contains(output.String(), "=== OidcIssuerCredentialIssued (verified)") {
+		t.Fatalf("Expected the event type in the output, got:\n%s", output)
+	}
+	if !strings.Contains(output.String(), "urn:uuid:0b6f2c1e-3a4d-4e5f-8a9b-0c1d2e3f4a5b") {
+		t.Fatalf("Expected the credential ID in the output, got:\n%s", output)
+	}
+
+	lines := strings.Split(strings.TrimSpace(events.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Expected one JSONL line, got %d", len(lines))
+	}
+	var recorded map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &recorded); err != nil {
+		t.Fatalf("Expected the line to be JSON: %s", err)
+	}
+}
+
+func TestListenerRejectsUnverifiedEvents(t *testing.T) {
+	listener, _, output, events := testListener(t)
+	_, otherPrivate, _ := ed25519.GenerateKey(rand.Reader)
+
+	w := httptest.NewRecorder()
+	listener.ServeHTTP(w, signedRequest(t, otherPrivate, testDelivery))
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("Expected 401, got %d", w.Code)
+	}
+	if output.Len() != 0 || events.Len() != 0 {
+		t.Fatal("Expected nothing to be printed or recorded")
+	}
+}
+
+func TestListenerDoesNotRecordInsecureEvents(t *testing.T) {
+	listener, _, output, events := testListener(t)
+	listener.Insecure = true
+
+	w := httptest.NewRecorder()
+	listener.ServeHTTP(w, httptest.NewRequest("POST", "http://localhost:8080/", strings.NewReader(testDelivery)))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body)
+	}
+	if !strings.Contains(output.String(), "=== OidcIssuerCredentialIssued (NOT verified)") {
+		t.Fatalf("Expected the unverified event in the output, got:\n%s", output)
+	}
+	if events.Len() != 0 {
+		t.Fatalf("Expected unverified events not to be recorded, got:\n%s", events)
+	}
+}
@@ -0,0 +1,119 @@
+// webhook-listener receives MATTR webhook requests on a local port, verifies
+// their signatures and prints them, to help when developing `mattr_webhook`
+// configurations. Expose it with a tunnel and set the webhook's `url` to the
+// tunnel's address.
+//
+// The signing keys are read from a JWKS file (e.g. the `jwks` attribute of
+// the `mattr_webhook_jwks` data source), or fetched from MATTR with the same
+// credentials as the provider, given as MATTR_API_URL, MATTR_CLIENT_ID,
+// MATTR_CLIENT_SECRET and optionally MATTR_AUTH_URL, MATTR_AUDIENCE or
+// MATTR_ACCESS_TOKEN.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"nz.antunovic/mattr-terraform-provider/api"
+	"nz.antunovic/mattr-terraform-provider/webhook"
+)
+
+// shutdownTimeout is how long in-flight requests have to finish after an
+// interrupt
+const shutdownTimeout = 10 * time.Second
+
+func main() {
+	addr := flag.String("addr", "localhost:8080", "Address to listen on")
+	jwksFile := flag.String("jwks", "", "JWKS file to verify signatures with. Fetched from MATTR if not set")
+	out := flag.String("out", "", "Append each verified event to this JSONL file")
+	maxAge := flag.Duration("max-age", 5*time.Minute, "Reject signatures older than this. A negative age accepts old signatures, allowing replays")
+	insecure := flag.Bool("insecure", false, "Print requests without verifying their signatures")
+	flag.Parse()
+
+	listener := Listener{
+		Output:   os.Stdout,
+		Insecure: *insecure,
+	}
+
+	if !*insecure {
+		jwks, err := loadJwks(*jwksFile)
+		if err != nil {
+			log.Fatalf("Unable to load the webhook JWKS: %s", err)
+		}
+		listener.Verifier = webhook.Verifier{Keys: jwks, MaxAge: *maxAge}
+	}
+
+	var events *os.File
+	if len(*out) != 0 {
+		var err error
+		events, err = os.OpenFile(*out, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+		if err != nil {
+			log.Fatalf("Unable to open %s: %s", *out, err)
+		}
+		listener.Events = events
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := &http.Server{Addr: *addr, Handler: &listener}
+	served := make(chan error, 1)
+	go func() {
+		log.Printf("Listening for webhooks on http://%s", *addr)
+		served <- server.ListenAndServe()
+	}()
+
+	var err error
+	select {
+	case err = <-served:
+	case <-ctx.Done():
+		log.Printf("Shutting down")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		err = server.Shutdown(shutdownCtx)
+		cancel()
+	}
+
+	// close the events file only once in-flight requests have finished
+	// writing to it
+	if events != nil {
+		if closeErr := events.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatal(err)
+	}
+}
+
+func loadJwks(path string) (*webhook.JWKS, error) {
+	if len(path) != 0 {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return webhook.ParseJWKS(data)
+	}
+
+	a := api.Api{
+		ApiUrl:       os.Getenv("MATTR_API_URL"),
+		AuthUrl:      os.Getenv("MATTR_AUTH_URL"),
+		Audience:     os.Getenv("MATTR_AUDIENCE"),
+		ClientId:     os.Getenv("MATTR_CLIENT_ID"),
+		ClientSecret: os.Getenv("MATTR_CLIENT_SECRET"),
+		AccessToken:  os.Getenv("MATTR_ACCESS_TOKEN"),
+	}
+	if len(a.ApiUrl) == 0 {
+		return nil, fmt.Errorf("Set -jwks, or MATTR_API_URL and credentials to fetch it")
+	}
+	a.Init()
+
+	return api.Get[webhook.JWKS](&a, "/core/v1/webhooks/jwks")
+}
@@ -1,21 +1,45 @@
 package generator
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"net/http"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"nz.antunovic/mattr-terraform-provider/api"
 )
 
+// DefaultTimeout applies to each operation of a generated resource unless the
+// generator declares its own Timeouts
+const DefaultTimeout = 2 * time.Minute
+
+const etagAttribute = "etag"
+
 type Generator struct {
-	Path        string
-	GetPath     func(*schema.ResourceData) (string, error)
-	Immutable   bool
-	Singleton   bool
-	Schema      map[string]*schema.Schema
-	Client      api.Client
-	Description string
+	Path      string
+	GetPath   func(*schema.ResourceData) (string, error)
+	Immutable bool
+	Singleton bool
+	// OptimisticConcurrency records the ETag of each response in a computed
+	// `etag` attribute and sends it as If-Match on update, so that changes
+	// made outside Terraform since the last refresh are not overwritten.
+	// Weak ETags can't be used with If-Match, so aren't sent.
+	OptimisticConcurrency bool
+	Schema                map[string]*schema.Schema
+	Client                api.Client
+	Description           string
+
+	// CustomizeDiff is passed through to the generated resource so that
+	// cross-field checks run at plan time
+	CustomizeDiff schema.CustomizeDiffFunc
+
+	// Timeouts are the defaults for the resource's `timeouts` block. Any
+	// operation left unset falls back to DefaultTimeout.
+	Timeouts *schema.ResourceTimeout
 
 	ModifyRequestBody  func(requestBody interface{}) (interface{}, error)
 	ModifyResponseBody func(responseBody interface{}) (interface{}, error)
@@ -24,23 +48,31 @@ type Generator struct {
 	ModifyResponse     func(headers *map[string]string, body *interface{}) error
 	ModifyResourceData func(resourceData *schema.ResourceData) error
 	GetId              func(requestBody *interface{}, responseBody *interface{}) string
+
+	// AfterOperation runs after a successful create, read or update, once the
+	// response has been set on the resource data. Unlike the modify functions
+	// it sees the resource's ID and the provider config, so it can set
+	// attributes derived from them, such as URLs on the tenant. The response
+	// is as the client returned it, although ModifyResponseBody may have
+	// changed its body in place.
+	AfterOperation func(ctx context.Context, d *schema.ResourceData, m interface{}, response *api.Response) error
 }
 
 func (generator *Generator) GenResource() schema.Resource {
-	create := func(d *schema.ResourceData, m interface{}) error {
-		return generator.sendRequestAndProcessResponse(d, m, "create")
+	create := func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		return diag.FromErr(generator.sendRequestAndProcessResponse(ctx, d, m, "create"))
 	}
 
-	read := func(d *schema.ResourceData, m interface{}) error {
-		return generator.sendRequestAndProcessResponse(d, m, "read")
+	read := func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		return diag.FromErr(generator.sendRequestAndProcessResponse(ctx, d, m, "read"))
 	}
 
-	update := func(d *schema.ResourceData, m interface{}) error {
-		return generator.sendRequestAndProcessResponse(d, m, "update")
+	update := func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		return diag.FromErr(generator.sendRequestAndProcessResponse(ctx, d, m, "update"))
 	}
 
-	deleteResource := func(d *schema.ResourceData, m interface{}) error {
-		return generator.sendRequestAndProcessResponse(d, m, "delete")
+	deleteResource := func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		return diag.FromErr(generator.sendRequestAndProcessResponse(ctx, d, m, "delete"))
 	}
 
 	var description = generator.Description
@@ -48,23 +80,80 @@ func (generator *Generator) GenResource() schema.Resource {
 		description = fmt.Sprintf("Represents the resource at %s", generator.Path)
 	}
 
+	resourceSchema := generator.Schema
+	if generator.OptimisticConcurrency {
+		resourceSchema = make(map[string]*schema.Schema, len(generator.Schema)+1)
+		for key, value := range generator.Schema {
+			resourceSchema[key] = value
+		}
+		resourceSchema[etagAttribute] = &schema.Schema{
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "ETag of the resource when it was last read, sent as If-Match on update",
+		}
+	}
+
 	resource := schema.Resource{
-		Description: description,
-		Create:      create,
-		Read:        read,
-		Delete:      deleteResource,
-		Schema:      generator.Schema,
+		Description:   description,
+		CreateContext: create,
+		ReadContext:   read,
+		DeleteContext: deleteResource,
+		Schema:        resourceSchema,
+		CustomizeDiff: generator.CustomizeDiff,
+		Timeouts:      generator.timeouts(),
 	}
 
 	if !generator.Immutable {
-		resource.Update = update
+		resource.UpdateContext = update
 	}
 
 	return resource
 }
 
-func (generator *Generator) sendRequestAndProcessResponse(d *schema.ResourceData, m interface{}, operation string) error {
-	api := m.(api.ProviderConfig).Api
+// GenDataSource generates a data source that reads from the generator's path.
+// The path must identify a single object, so data sources usually set GetPath
+// and Singleton.
+func (generator *Generator) GenDataSource() schema.Resource {
+	read := func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		return diag.FromErr(generator.sendRequestAndProcessResponse(ctx, d, m, "read"))
+	}
+
+	var description = generator.Description
+	if len(generator.Path) != 0 {
+		description = fmt.Sprintf("Reads the resource at %s", generator.Path)
+	}
+
+	return schema.Resource{
+		Description: description,
+		ReadContext: read,
+		Schema:      generator.Schema,
+		Timeouts: &schema.ResourceTimeout{
+			Read: generator.timeouts().Read,
+		},
+	}
+}
+
+func (generator *Generator) timeouts() *schema.ResourceTimeout {
+	timeouts := schema.ResourceTimeout{}
+	if generator.Timeouts != nil {
+		timeouts = *generator.Timeouts
+	}
+
+	for _, timeout := range []**time.Duration{&timeouts.Create, &timeouts.Read, &timeouts.Update, &timeouts.Delete} {
+		if *timeout == nil {
+			*timeout = schema.DefaultTimeout(DefaultTimeout)
+		}
+	}
+
+	if generator.Immutable {
+		timeouts.Update = nil
+	}
+
+	return &timeouts
+}
+
+func (generator *Generator) sendRequestAndProcessResponse(ctx context.Context, d *schema.ResourceData, m interface{}, operation string) error {
+	providerApi := m.(api.ProviderConfig).Api
 	requestVisitor := RequestVisitor{
 		schema: generator.Schema,
 	}
@@ -82,7 +171,7 @@ func (generator *Generator) sendRequestAndProcessResponse(d *schema.ResourceData
 
 	log.Printf("Going to send request for resource: %s", path)
 
-	url, err := api.GetUrl(path)
+	url, err := providerApi.GetUrl(path)
 	if err != nil {
 		return err
 	}
@@ -95,12 +184,21 @@ func (generator *Generator) sendRequestAndProcessResponse(d *schema.ResourceData
 	log.Printf("Full resource URL is: %s", fullUrl)
 	log.Printf("Getting access token for %s", fullUrl)
 
-	accessToken, err := api.GetAccessToken()
+	headers, err := providerApi.AuthHeaders(ctx)
 	if err != nil {
 		return err
 	}
-	headers := map[string]string{
-		"Authorization": "Bearer " + accessToken,
+
+	if generator.OptimisticConcurrency && operation == "update" {
+		if etag, ok := d.Get(etagAttribute).(string); ok && len(etag) != 0 {
+			if strings.HasPrefix(etag, "W/") {
+				// If-Match uses strong comparison, which a weak ETag never passes
+				log.Printf("Not sending weak ETag %s as If-Match for %s", etag, fullUrl)
+			} else {
+				log.Printf("Updating %s only if it still matches ETag %s", fullUrl, etag)
+				headers["If-Match"] = etag
+			}
+		}
 	}
 
 	var body interface{}
@@ -130,22 +228,22 @@ func (generator *Generator) sendRequestAndProcessResponse(d *schema.ResourceData
 	}
 
 	// send request
-	var response interface{}
+	var apiResponse *api.Response
 	switch operation {
 	case "create":
-		response, err = generator.Client.Post(fullUrl, headers, body)
+		apiResponse, err = generator.Client.Post(ctx, fullUrl, headers, body)
 	case "read":
-		response, err = generator.Client.Get(fullUrl, headers)
+		apiResponse, err = generator.Client.Get(ctx, fullUrl, headers)
 	case "update":
-		response, err = generator.Client.Put(fullUrl, headers, body)
+		apiResponse, err = generator.Client.Put(ctx, fullUrl, headers, body)
 	case "delete":
-		err = generator.Client.Delete(fullUrl, headers)
+		err = generator.Client.Delete(ctx, fullUrl, headers)
 	default:
 		return fmt.Errorf("unknown operation: %s", operation)
 	}
 
 	if err != nil {
-		return err
+		return explainPreconditionFailed(err, fullUrl)
 	}
 
 	// on successful delete, exit early
@@ -154,6 +252,12 @@ func (generator *Generator) sendRequestAndProcessResponse(d *schema.ResourceData
 		return nil
 	}
 
+	response := apiResponse.Body
+	responseHeaders := apiResponse.Headers
+	if responseHeaders == nil {
+		responseHeaders = map[string]string{}
+	}
+
 	// modify response
 	if generator.ModifyResponseBody != nil {
 		log.Printf("Modifying response body for %s", fullUrl)
@@ -164,7 +268,7 @@ func (generator *Generator) sendRequestAndProcessResponse(d *schema.ResourceData
 	}
 	if generator.ModifyResponse != nil {
 		log.Printf("Modifying response for %s", fullUrl)
-		err = generator.ModifyResponse(&map[string]string{}, &response) // TODO response headers
+		err = generator.ModifyResponse(&responseHeaders, &response)
 		if err != nil {
 			return err
 		}
@@ -195,5 +299,23 @@ func (generator *Generator) sendRequestAndProcessResponse(d *schema.ResourceData
 		}
 	}
 
+	if generator.OptimisticConcurrency {
+		if err := d.Set(etagAttribute, responseHeaders["Etag"]); err != nil {
+			return err
+		}
+	}
+
+	if generator.AfterOperation != nil && len(d.Id()) != 0 {
+		log.Printf("Running post-%s hook for %s", operation, fullUrl)
+		return generator.AfterOperation(ctx, d, m, apiResponse)
+	}
+
 	return nil
 }
+
+func explainPreconditionFailed(err error, url string) error {
+	if apiError, ok := err.(api.ApiError); ok && apiError.StatusCode == http.StatusPreconditionFailed {
+		return fmt.Errorf("%s was changed outside of Terraform since it was last read. Refresh and review the plan before applying again.\n%s", url, err)
+	}
+	return err
+}
@@ -0,0 +1,207 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"nz.antunovic/mattr-terraform-provider/api"
+)
+
+type testRequest struct {
+	method  string
+	url     string
+	headers map[string]string
+	body    interface{}
+}
+
+// testClient responds to "METHOD url" with the body in responses, or fails
+// with the error in errors
+type testClient struct {
+	logs      []testRequest
+	responses map[string]interface{}
+	headers   map[string]map[string]string
+	errors    map[string]error
+}
+
+func (client *testClient) Post(ctx context.Context, url string, headers map[string]string, body interface{}) (*api.Response, error) {
+	return client.respond("POST", url, headers, body)
+}
+
+func (client *testClient) Get(ctx context.Context, url string, headers map[string]string) (*api.Response, error) {
+	return client.respond("GET", url, headers, nil)
+}
+
+func (client *testClient) Put(ctx context.Context, url string, headers map[string]string, body interface{}) (*api.Response, error) {
+	return client.respond("PUT", url, headers, body)
+}
+
+func (client *testClient) Delete(ctx context.Context, url string, headers map[string]string) error {
+	_, err := client.respond("DELETE", url, headers, nil)
+	return err
+}
+
+func (client *testClient) respond(method string, url string, headers map[string]string, body interface{}) (*api.Response, error) {
+	endpoint := fmt.Sprintf("%s %s", method, url)
+	requestHeaders := make(map[string]string, len(headers))
+	for key, value := range headers {
+		requestHeaders[key] = value
+	}
+	client.logs = append(client.logs, testRequest{method: method, url: url, headers: requestHeaders, body: body})
+
+	if err, ok := client.errors[endpoint]; ok {
+		return nil, err
+	}
+	response, ok := client.responses[endpoint]
+	if !ok {
+		return nil, fmt.Errorf("Unable to find response for %s", endpoint)
+	}
+	return &api.Response{StatusCode: 200, Headers: client.headers[endpoint], Body: response}, nil
+}
+
+func testMeta() api.ProviderConfig {
+	return api.ProviderConfig{
+		Api: api.Api{
+			ApiUrl:               "https://test.api",
+			AccessToken:          "test-token",
+			AccessTokenExpiresAt: math.MaxInt,
+		},
+	}
+}
+
+var testSchema = map[string]*schema.Schema{
+	"name": &schema.Schema{
+		Type:     schema.TypeString,
+		Required: true,
+	},
+	"description": &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+	},
+}
+
+// applyUpdate creates a thing named "before", then updates its name to
+// "after", returning the new state
+func applyUpdate(t *testing.T, generator *Generator) (*terraform.InstanceState, error) {
+	resource := generator.GenResource()
+
+	d := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{
+		"name":        "before",
+		"description": "unchanged",
+	})
+	if diags := resource.CreateContext(context.Background(), d, testMeta()); diags.HasError() {
+		t.Fatalf("Create failed: %v", diags)
+	}
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":        "after",
+		"description": "unchanged",
+	})
+	diff, err := resource.Diff(context.Background(), d.State(), config, testMeta())
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+
+	state, diags := resource.Apply(context.Background(), d.State(), diff, testMeta())
+	if diags.HasError() {
+		return state, fmt.Errorf("%v", diags)
+	}
+	return state, nil
+}
+
+func TestUpdateSendsFullBody(t *testing.T) {
+	client := testClient{
+		responses: map[string]interface{}{
+			"POST https://test.api/things":  map[string]interface{}{"id": "1", "name": "before", "description": "unchanged"},
+			"PUT https://test.api/things/1": map[string]interface{}{"id": "1", "name": "after", "description": "unchanged"},
+		},
+	}
+	generator := Generator{Path: "/things", Client: &client, Schema: testSchema}
+
+	state, err := applyUpdate(t, &generator)
+	if err != nil {
+		t.Fatalf("Update failed: %s", err)
+	}
+
+	request := client.logs[len(client.logs)-1]
+	expected := map[string]interface{}{"name": "after", "description": "unchanged"}
+	if !reflect.DeepEqual(expected, request.body) {
+		t.Errorf("PUT replaces the object, so unchanged attributes should be sent too, got %v", request.body)
+	}
+	if state.Attributes["name"] != "after" {
+		t.Errorf("Name should be updated, got %q", state.Attributes["name"])
+	}
+}
+
+func TestOptimisticConcurrencySendsStrongETag(t *testing.T) {
+	client := testClient{
+		responses: map[string]interface{}{
+			"POST https://test.api/things":  map[string]interface{}{"id": "1", "name": "before"},
+			"PUT https://test.api/things/1": map[string]interface{}{"id": "1", "name": "after"},
+		},
+		headers: map[string]map[string]string{
+			"POST https://test.api/things": {"Etag": `"1"`},
+		},
+	}
+	generator := Generator{Path: "/things", Client: &client, Schema: testSchema, OptimisticConcurrency: true}
+
+	if _, err := applyUpdate(t, &generator); err != nil {
+		t.Fatalf("Update failed: %s", err)
+	}
+	if ifMatch := client.logs[len(client.logs)-1].headers["If-Match"]; ifMatch != `"1"` {
+		t.Errorf("Update should send the ETag from create, got %q", ifMatch)
+	}
+}
+
+func TestOptimisticConcurrencySkipsWeakETag(t *testing.T) {
+	client := testClient{
+		responses: map[string]interface{}{
+			"POST https://test.api/things":  map[string]interface{}{"id": "1", "name": "before"},
+			"PUT https://test.api/things/1": map[string]interface{}{"id": "1", "name": "after"},
+		},
+		headers: map[string]map[string]string{
+			"POST https://test.api/things": {"Etag": `W/"1"`},
+		},
+	}
+	generator := Generator{Path: "/things", Client: &client, Schema: testSchema, OptimisticConcurrency: true}
+
+	if _, err := applyUpdate(t, &generator); err != nil {
+		t.Fatalf("Update failed: %s", err)
+	}
+	if ifMatch, ok := client.logs[len(client.logs)-1].headers["If-Match"]; ok {
+		t.Errorf("A weak ETag should not be sent as If-Match, got %q", ifMatch)
+	}
+}
+
+func TestExplainPreconditionFailed(t *testing.T) {
+	client := testClient{
+		responses: map[string]interface{}{
+			"POST https://test.api/things": map[string]interface{}{"id": "1", "name": "before"},
+		},
+		headers: map[string]map[string]string{
+			"POST https://test.api/things": {"Etag": `"1"`},
+		},
+		errors: map[string]error{
+			"PUT https://test.api/things/1": api.ApiError{StatusCode: 412, Message: "Precondition Failed"},
+		},
+	}
+	generator := Generator{Path: "/things", Client: &client, Schema: testSchema, OptimisticConcurrency: true}
+
+	_, err := applyUpdate(t, &generator)
+	if err == nil {
+		t.Fatal("Update should fail when the ETag no longer matches")
+	}
+	if !strings.Contains(err.Error(), "was changed outside of Terraform") {
+		t.Errorf("The error should explain the conflict, got %s", err)
+	}
+
+	other := api.ApiError{StatusCode: 400, Message: "Invalid name"}
+	if explained := explainPreconditionFailed(other, "https://test.api/things/1"); !reflect.DeepEqual(explained, error(other)) {
+		t.Errorf("Other errors should be returned as they are, got %s", explained)
+	}
+}
@@ -0,0 +1,56 @@
+package generator
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// JsonAttribute flags a string attribute as holding JSON. The JSON is checked
+// at plan time, and stored normalised so that whitespace and key order that
+// differ from MATTR's serialisation don't show as changes.
+func JsonAttribute(attribute *schema.Schema) *schema.Schema {
+	attribute.ValidateFunc = validation.StringIsJSON
+	attribute.StateFunc = NormaliseJson
+	attribute.DiffSuppressFunc = SuppressJsonDiff
+	return attribute
+}
+
+// NormaliseJson serialises JSON the same way as a response from MATTR is: with
+// no whitespace and with object keys sorted. Invalid JSON is returned as it
+// is, for validation to report.
+func NormaliseJson(value interface{}) string {
+	jsonString, _ := value.(string)
+	if len(jsonString) == 0 {
+		return ""
+	}
+
+	var parsed interface{}
+	if err := json.Unmarshal([]byte(jsonString), &parsed); err != nil {
+		return jsonString
+	}
+	normalised, err := json.Marshal(parsed)
+	if err != nil {
+		return jsonString
+	}
+	return string(normalised)
+}
+
+// SuppressJsonDiff ignores changes between JSON values that are semantically
+// equal
+func SuppressJsonDiff(key, oldValue, newValue string, d *schema.ResourceData) bool {
+	if oldValue == newValue {
+		return true
+	}
+
+	var oldParsed, newParsed interface{}
+	if err := json.Unmarshal([]byte(oldValue), &oldParsed); err != nil {
+		return false
+	}
+	if err := json.Unmarshal([]byte(newValue), &newParsed); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(oldParsed, newParsed)
+}
@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// Catalogue of the enumerated values accepted by the MATTR API. Schemas
+// validate against these so that typos are caught by `terraform validate`
+// instead of surfacing as a 400 from MATTR during apply.
+var (
+	didMethods = []string{"key", "web", "ion"}
+
+	// did:web always creates one key of each type, so has no choice of key
+	didKeyTypes = map[string][]string{
+		"key": {"ed25519", "bls12381g2", "p256"},
+		"ion": {"ed25519", "bls12381g2"},
+		"web": {},
+	}
+
+	proofTypes = []string{"Ed25519Signature2018", "BbsBlsSignature2020"}
+
+	tokenEndpointAuthMethods = []string{"client_secret_post", "client_secret_basic", "none"}
+
+	idTokenSignedResponseAlgs = []string{"ES256", "EdDSA"}
+
+	applicationTypes = []string{"web", "native"}
+
+	responseTypes = []string{"code"}
+
+	grantTypes = []string{"authorization_code"}
+
+	codeChallengeMethods = []string{"S256", "plain"}
+
+	webhookEvents = []string{"OidcIssuerCredentialIssued", "PresentationSubmitted"}
+
+	claimSourceAuthorizationTypes = []string{"api-key", "bearer"}
+
+	mdocClaimTypes = []string{"string", "number", "boolean", "date", "dateTime"}
+
+	trustedIssuerStatuses = []string{"active", "inactive"}
+
+	verifierApplicationTypes = []string{"web", "ios", "android"}
+
+	resultDeliveryModes = []string{"front_channel", "back_channel"}
+
+	ecosystemParticipantRoles = []string{"issuer", "verifier"}
+)
+
+// oneOf validates that a string attribute is one of the allowed values.
+// Matching is case sensitive, as it is in the MATTR API.
+func oneOf(allowed []string) schema.SchemaValidateFunc {
+	return validation.StringInSlice(allowed, false)
+}
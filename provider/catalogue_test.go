@@ -0,0 +1,36 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestResourceDidRejectsUnknownMethod(t *testing.T) {
+	resource := resourceDid(&TestClient{})
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"method": "wbe",
+	})
+
+	diags := resource.Validate(config)
+	if !diags.HasError() {
+		t.Fatal("Expected an error for an unknown DID method")
+	}
+}
+
+func TestResourceVerifierClientRejectsUnknownAlg(t *testing.T) {
+	resource := resourceVerifierClient(&TestClient{})
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"verifier_id":                  "402c65eb-48e9-4a4c-b5e9-1ea615baccee",
+		"name":                         "OIDC Client for the verifier",
+		"redirect_uris":                []interface{}{"https://example.com/callback"},
+		"response_types":               []interface{}{"code"},
+		"grant_types":                  []interface{}{"authorization_code"},
+		"id_token_signed_response_alg": "RS256",
+	})
+
+	diags := resource.Validate(config)
+	if !diags.HasError() {
+		t.Fatal("Expected an error for an unsupported id_token_signed_response_alg")
+	}
+}
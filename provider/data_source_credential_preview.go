@@ -0,0 +1,130 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// dataSourceCredentialPreview applies claim mappings to sample claims, the way
+// MATTR does when a credential is claimed, so that mappings can be checked
+// without issuing one. It makes no requests to MATTR.
+func dataSourceCredentialPreview() *schema.Resource {
+	read := func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		var claims interface{}
+		if err := json.Unmarshal([]byte(d.Get("claims").(string)), &claims); err != nil {
+			return diag.Errorf("Unable to parse 'claims': %s", err)
+		}
+		user := map[string]interface{}{"claims": claims}
+
+		credentialSubject := make(map[string]interface{})
+		missing := make([]string, 0)
+		for _, claimMapping := range d.Get("claim_mapping").(*schema.Set).List() {
+			claimMappingMap := claimMapping.(map[string]interface{})
+			name := claimMappingMap["name"].(string)
+
+			if mapFrom, _ := claimMappingMap["map_from"].(string); len(mapFrom) != 0 {
+				if value, ok := resolveClaimPath(user, mapFrom); ok {
+					credentialSubject[name] = value
+					continue
+				}
+			}
+			if defaultValue, _ := claimMappingMap["default_value"].(string); len(defaultValue) != 0 {
+				credentialSubject[name] = defaultValue
+				continue
+			}
+			if required, _ := claimMappingMap["required"].(bool); required {
+				missing = append(missing, name)
+			}
+		}
+
+		if len(missing) != 0 {
+			sort.Strings(missing)
+			return diag.Errorf("The sample claims have no value for required claims: %s", strings.Join(missing, ", "))
+		}
+
+		credentialSubjectJson, err := json.Marshal(credentialSubject)
+		if err != nil {
+			return diag.Errorf("Unable to serialise credential subject: %s", err)
+		}
+		// the subject can be large, so it's identified by its hash
+		hash := sha256.Sum256(credentialSubjectJson)
+		d.SetId(hex.EncodeToString(hash[:]))
+		return diag.FromErr(d.Set("credential_subject", string(credentialSubjectJson)))
+	}
+
+	return &schema.Resource{
+		Description: "Previews the credential subject that claim mappings produce from sample claims, without calling MATTR. Only credential claim mappings are previewed, which map from `claims`; claim source request parameters that map from `credentialConfiguration` aren't.",
+		ReadContext: read,
+		Schema: map[string]*schema.Schema{
+			"claim_mapping": claimMappingSchema(),
+			"claims": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsJSON,
+				Description:  "Sample claims of a user as JSON, which map_from paths starting with `claims` refer to",
+			},
+			"credential_subject": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The claims the credential would have, as JSON",
+			},
+		},
+	}
+}
+
+// resolveClaimPath finds the value at a map_from path, e.g. claims.emails[0]
+func resolveClaimPath(data interface{}, path string) (interface{}, bool) {
+	return resolveClaimSegments(data, strings.Split(path, "."))
+}
+
+// resolveClaimSegments looks each level of a path up in turn. Claim names can
+// contain dots, e.g. URLs, so a level can span several segments.
+func resolveClaimSegments(value interface{}, segments []string) (interface{}, bool) {
+	if len(segments) == 0 {
+		return value, value != nil
+	}
+
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	for end := 1; end <= len(segments); end++ {
+		key, indices, _ := strings.Cut(strings.Join(segments[:end], "."), "[")
+		child, ok := object[key]
+		if !ok {
+			continue
+		}
+		if child, ok = indexClaim(child, indices); !ok {
+			continue
+		}
+		if resolved, ok := resolveClaimSegments(child, segments[end:]); ok {
+			return resolved, true
+		}
+	}
+	return nil, false
+}
+
+// indexClaim applies list indices such as "0][1]", left after the first "["
+func indexClaim(value interface{}, indices string) (interface{}, bool) {
+	if len(indices) == 0 {
+		return value, true
+	}
+	for _, index := range strings.Split(strings.TrimSuffix(indices, "]"), "][") {
+		i, err := strconv.Atoi(index)
+		list, ok := value.([]interface{})
+		if err != nil || !ok || i < 0 || i >= len(list) {
+			return nil, false
+		}
+		value = list[i]
+	}
+	return value, true
+}
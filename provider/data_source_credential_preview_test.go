@@ -0,0 +1,41 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestDataSourceCredentialPreview(t *testing.T) {
+	dataSource := dataSourceCredentialPreview()
+	readData := runRead(t, dataSource, map[string]interface{}{
+		"claims": `{"given_name": "Alice", "address": {"locality": "Wellington"}, "emails": ["alice@example.com"], "https://example.com/employee": {"id": "E123"}}`,
+		"claim_mapping": []interface{}{
+			map[string]interface{}{"name": "firstName", "map_from": "claims.given_name", "required": true},
+			map[string]interface{}{"name": "city", "map_from": "claims.address.locality"},
+			map[string]interface{}{"name": "email", "map_from": "claims.emails[0]"},
+			map[string]interface{}{"name": "country", "map_from": "claims.address.country", "default_value": "NZ"},
+			map[string]interface{}{"name": "nickname", "map_from": "claims.nickname"},
+			map[string]interface{}{"name": "employeeId", "map_from": "claims.https://example.com/employee.id"},
+		},
+	}, &TestClient{})
+
+	AssertEqual(t, `{"city":"Wellington","country":"NZ","email":"alice@example.com","employeeId":"E123","firstName":"Alice"}`, readData.Get("credential_subject"), "Credential subject should be mapped from the claims")
+	AssertEqual(t, 64, len(readData.Id()), "ID should be a SHA-256 hash of the credential subject")
+}
+
+func TestDataSourceCredentialPreviewMissingRequiredClaim(t *testing.T) {
+	dataSource := dataSourceCredentialPreview()
+	readData := schema.TestResourceDataRaw(t, dataSource.Schema, map[string]interface{}{
+		"claims": `{"given_name": "Alice"}`,
+		"claim_mapping": []interface{}{
+			map[string]interface{}{"name": "lastName", "map_from": "claims.family_name", "required": true},
+		},
+	})
+
+	diags := dataSource.ReadContext(context.Background(), readData, testProviderConfig())
+	if !diags.HasError() {
+		t.Fatal("Expected an error for a required claim with no value")
+	}
+}
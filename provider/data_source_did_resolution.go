@@ -0,0 +1,210 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"nz.antunovic/mattr-terraform-provider/api"
+	"nz.antunovic/mattr-terraform-provider/generator"
+)
+
+func dataSourceDidResolution(client api.Client) *schema.Resource {
+	didResolutionSchema := map[string]*schema.Schema{
+		"did": &schema.Schema{
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The DID to resolve, which can belong to any tenant or none",
+		},
+		"did_document": &schema.Schema{
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The resolved DID document as JSON",
+		},
+		"verification_method": &schema.Schema{
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"id": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"type": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"controller": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"public_key_jwk": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"public_key_multibase": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+				},
+			},
+		},
+		"authentication": &schema.Schema{
+			Type:        schema.TypeList,
+			Computed:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Description: "IDs of the verification methods used for authentication",
+		},
+		"assertion_method": &schema.Schema{
+			Type:        schema.TypeList,
+			Computed:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Description: "IDs of the verification methods used to issue credentials",
+		},
+		"service": &schema.Schema{
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"id": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"type": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"service_endpoint": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "The endpoint URL, or JSON if the endpoint is a map or list",
+					},
+				},
+			},
+		},
+	}
+
+	getPath := func(d *schema.ResourceData) (string, error) {
+		if did, ok := d.Get("did").(string); ok && len(did) != 0 {
+			return didResolvePath(did), nil
+		}
+
+		return "", fmt.Errorf("'did' field is required for DID resolution and must be a string")
+	}
+
+	generator := generator.Generator{
+		GetPath:            getPath,
+		Singleton:          true,
+		Client:             client,
+		Schema:             didResolutionSchema,
+		Description:        fmt.Sprintf("Resolves a DID using %s", didResolvePath("{did}")),
+		ModifyResponseBody: convertDidResolutionRes,
+		AfterOperation:     setDidResolutionMethodsAndServices,
+	}
+
+	dataSource := generator.GenDataSource()
+	return &dataSource
+}
+
+func convertDidResolutionRes(body interface{}) (interface{}, error) {
+	bodyMap, ok := body.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Unexpected type for DID resolution: %T", body)
+	}
+
+	didDocument, ok := bodyMap["didDocument"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Unexpected type for DID resolution 'didDocument' field: %T", bodyMap["didDocument"])
+	}
+
+	didDocumentJson, err := json.Marshal(didDocument)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to serialise DID document: %s", err)
+	}
+
+	return map[string]interface{}{
+		"id":              didDocument["id"],
+		"didDocument":     string(didDocumentJson),
+		"authentication":  didVerificationRelationship(didDocument, "authentication"),
+		"assertionMethod": didVerificationRelationship(didDocument, "assertionMethod"),
+	}, nil
+}
+
+// setDidResolutionMethodsAndServices sets the verification methods and
+// services, which have their own IDs that the response visitor would take for
+// the DID's
+func setDidResolutionMethodsAndServices(ctx context.Context, d *schema.ResourceData, m interface{}, response *api.Response) error {
+	body, _ := response.Body.(map[string]interface{})
+	didDocument, ok := body["didDocument"].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("Unexpected type for DID resolution 'didDocument' field: %T", body["didDocument"])
+	}
+
+	if err := setDidVerificationMethods(d, didDocument); err != nil {
+		return err
+	}
+
+	services, err := didServices(didDocument)
+	if err != nil {
+		return err
+	}
+	return d.Set("service", services)
+}
+
+// didVerificationRelationship lists the IDs of the verification methods in a
+// relationship such as assertionMethod, whether they are referenced or
+// embedded
+func didVerificationRelationship(didDocument map[string]interface{}, relationship string) []interface{} {
+	ids := make([]interface{}, 0)
+	methods, _ := didDocument[relationship].([]interface{})
+	for _, method := range methods {
+		switch method := method.(type) {
+		case string:
+			ids = append(ids, method)
+		case map[string]interface{}:
+			if id, ok := method["id"].(string); ok {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+func didServices(didDocument map[string]interface{}) ([]interface{}, error) {
+	services := make([]interface{}, 0)
+	serviceList, _ := didDocument["service"].([]interface{})
+	for i, service := range serviceList {
+		serviceMap, ok := service.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Unexpected type for DID document service %d: %T", i, service)
+		}
+
+		// endpoints can be a URL, a map or a list of either
+		endpoint, ok := serviceMap["serviceEndpoint"].(string)
+		if !ok {
+			endpointJson, err := json.Marshal(serviceMap["serviceEndpoint"])
+			if err != nil {
+				return nil, fmt.Errorf("Unable to serialise endpoint of DID document service %d: %s", i, err)
+			}
+			endpoint = string(endpointJson)
+		}
+
+		// the type can also be a list of types
+		serviceType, ok := serviceMap["type"].(string)
+		if !ok {
+			serviceTypeJson, err := json.Marshal(serviceMap["type"])
+			if err != nil {
+				return nil, fmt.Errorf("Unable to serialise type of DID document service %d: %s", i, err)
+			}
+			serviceType = string(serviceTypeJson)
+		}
+
+		services = append(services, map[string]interface{}{
+			"id":               serviceMap["id"],
+			"type":             serviceType,
+			"service_endpoint": endpoint,
+		})
+	}
+	return services, nil
+}
@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDataSourceDidResolution(t *testing.T) {
+	jsonData := `{
+	"didDocument": {
+		"@context": ["https://www.w3.org/ns/did/v1"],
+		"id": "did:web:issuer.example.com",
+		"verificationMethod": [
+			{
+				"id": "did:web:issuer.example.com#key-1",
+				"type": "Ed25519VerificationKey2018",
+				"controller": "did:web:issuer.example.com",
+				"publicKeyBase58": "2vcj3MjR4dSKq5asFQ9oor7iZsqTKTfBpjLHgaP15Y24"
+			}
+		],
+		"authentication": ["did:web:issuer.example.com#key-1"],
+		"assertionMethod": ["did:web:issuer.example.com#key-1"],
+		"service": [
+			{
+				"id": "did:web:issuer.example.com#linked-domain",
+				"type": "LinkedDomains",
+				"serviceEndpoint": "https://issuer.example.com"
+			},
+			{
+				"id": "did:web:issuer.example.com#hub",
+				"type": "IdentityHub",
+				"serviceEndpoint": {"instances": ["https://hub.example.com"]}
+			}
+		]
+	}
+}`
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(jsonData), &data); err != nil {
+		t.Fatalf("Error in test data: %s", err)
+	}
+
+	client := TestClient{
+		responses: map[string]interface{}{
+			"GET https://test.api/core/v1/dids/did:web:issuer.example.com": data,
+		},
+	}
+
+	dataSource := dataSourceDidResolution(&client)
+	resourceData := runRead(t, dataSource, map[string]interface{}{
+		"did": "did:web:issuer.example.com",
+	}, &client)
+
+	AssertEqual(t, "did:web:issuer.example.com", resourceData.Id(), "ID should be the DID")
+	AssertEqual(t, []interface{}{"did:web:issuer.example.com#key-1"}, resourceData.Get("assertion_method"), "Assertion methods should match")
+	AssertEqual(t, "did:web:issuer.example.com#key-1", resourceData.Get("verification_method.0.id"), "Verification method ID should match")
+	AssertEqual(t, "z6MkgNsmdbyrQAvnwaRZvy7eewfiPT7JjLuYWkFDWrM1zkoS", resourceData.Get("verification_method.0.public_key_multibase"), "Public key should be a Multikey")
+	AssertEqual(t, "did:web:issuer.example.com#linked-domain", resourceData.Get("service.0.id"), "Service ID should match")
+	AssertEqual(t, "https://issuer.example.com", resourceData.Get("service.0.service_endpoint"), "URL endpoints should be kept as is")
+	AssertEqual(t, `{"instances":["https://hub.example.com"]}`, resourceData.Get("service.1.service_endpoint"), "Map endpoints should be JSON")
+}
@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"nz.antunovic/mattr-terraform-provider/api"
+	"nz.antunovic/mattr-terraform-provider/generator"
+)
+
+const revocationListPath = "/v2/credentials/web-semantic/revocation-lists"
+
+// dataSourceRevocationList reads a web credential revocation list. MATTR
+// creates the lists itself as revocable credentials are issued and keeps them
+// for as long as those credentials, so they can only be looked up.
+func dataSourceRevocationList(client api.Client) *schema.Resource {
+	revocationListSchema := map[string]*schema.Schema{
+		"revocation_list_id": &schema.Schema{
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "ID of the revocation list, as found in the credentialStatus of a revocable credential",
+		},
+		"revocation_list": &schema.Schema{
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The revocation list as JSON",
+		},
+	}
+
+	getPath := func(d *schema.ResourceData) (string, error) {
+		if id, ok := d.Get("revocation_list_id").(string); ok && len(id) != 0 {
+			return fmt.Sprintf("%s/%s", revocationListPath, id), nil
+		}
+
+		return "", fmt.Errorf("'revocation_list_id' field is required and must be a string")
+	}
+
+	generator := generator.Generator{
+		GetPath:            getPath,
+		Singleton:          true,
+		Client:             client,
+		Schema:             revocationListSchema,
+		Description:        fmt.Sprintf("Reads a revocation list using %s/{id}", revocationListPath),
+		ModifyResponseBody: convertRevocationListRes,
+	}
+
+	dataSource := generator.GenDataSource()
+
+	// the response isn't guaranteed to include the list's `id`, so the ID is
+	// always the one it was looked up by
+	read := dataSource.ReadContext
+	dataSource.ReadContext = func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		if diags := read(ctx, d, m); diags.HasError() {
+			return diags
+		}
+		d.SetId(d.Get("revocation_list_id").(string))
+		return nil
+	}
+
+	return &dataSource
+}
+
+func convertRevocationListRes(body interface{}) (interface{}, error) {
+	bodyMap, ok := body.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Unexpected type for %s: %T", revocationListPath, body)
+	}
+
+	revocationListJson, err := json.Marshal(bodyMap)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to serialise revocation list: %s", err)
+	}
+
+	return map[string]interface{}{
+		"revocationList": string(revocationListJson),
+	}, nil
+}
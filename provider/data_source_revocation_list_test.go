@@ -0,0 +1,42 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestDataSourceRevocationList(t *testing.T) {
+	client := TestClient{
+		responses: map[string]interface{}{
+			"GET https://test.api/v2/credentials/web-semantic/revocation-lists/5e8e2bd4-1f24-4d3c-9c5e-4d6e7f8a9b0c": map[string]interface{}{
+				"id":        "5e8e2bd4-1f24-4d3c-9c5e-4d6e7f8a9b0c",
+				"createdAt": "2024-01-02T03:04:05Z",
+			},
+		},
+	}
+
+	dataSource := dataSourceRevocationList(&client)
+	resourceData := runRead(t, dataSource, map[string]interface{}{
+		"revocation_list_id": "5e8e2bd4-1f24-4d3c-9c5e-4d6e7f8a9b0c",
+	}, &client)
+
+	AssertEqual(t, "5e8e2bd4-1f24-4d3c-9c5e-4d6e7f8a9b0c", resourceData.Id(), "ID should match")
+	AssertEqual(t, `{"createdAt":"2024-01-02T03:04:05Z","id":"5e8e2bd4-1f24-4d3c-9c5e-4d6e7f8a9b0c"}`, resourceData.Get("revocation_list"), "Revocation list should be JSON")
+	AssertEqual(t, 1, len(client.logs), "The list should only be read")
+}
+
+func TestDataSourceRevocationListIdIsTheArgument(t *testing.T) {
+	client := TestClient{
+		responses: map[string]interface{}{
+			"GET https://test.api/v2/credentials/web-semantic/revocation-lists/5e8e2bd4-1f24-4d3c-9c5e-4d6e7f8a9b0c": map[string]interface{}{
+				"createdAt": "2024-01-02T03:04:05Z",
+			},
+		},
+	}
+
+	dataSource := dataSourceRevocationList(&client)
+	resourceData := runRead(t, dataSource, map[string]interface{}{
+		"revocation_list_id": "5e8e2bd4-1f24-4d3c-9c5e-4d6e7f8a9b0c",
+	}, &client)
+
+	AssertEqual(t, "5e8e2bd4-1f24-4d3c-9c5e-4d6e7f8a9b0c", resourceData.Id(), "ID should be the revocation list ID even when the list has none")
+}
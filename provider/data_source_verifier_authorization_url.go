@@ -0,0 +1,120 @@
+package provider
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// dataSourceVerifierAuthorizationUrl builds the URL that a relying party
+// sends users to, to present credentials to a verifier over OIDC. It makes no
+// requests to MATTR.
+func dataSourceVerifierAuthorizationUrl() *schema.Resource {
+	read := func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		verifierId := d.Get("verifier_id").(string)
+
+		authorizationUrl, err := verifierOidcUrl(m, verifierId, "/authorize")
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		openidConfigurationUrl, err := verifierOidcUrl(m, verifierId, "/.well-known/openid-configuration")
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		scope := make([]string, 0)
+		for _, value := range d.Get("scope").([]interface{}) {
+			scope = append(scope, value.(string))
+		}
+		if len(scope) == 0 {
+			scope = append(scope, "openid")
+		}
+
+		query := url.Values{}
+		query.Set("client_id", d.Get("client_id").(string))
+		query.Set("redirect_uri", d.Get("redirect_uri").(string))
+		query.Set("response_type", d.Get("response_type").(string))
+		query.Set("scope", strings.Join(scope, " "))
+		for _, parameter := range []string{"state", "nonce", "code_challenge"} {
+			if value, ok := d.GetOk(parameter); ok {
+				query.Set(parameter, value.(string))
+			}
+		}
+		if _, ok := d.GetOk("code_challenge"); ok {
+			query.Set("code_challenge_method", d.Get("code_challenge_method").(string))
+		}
+
+		url := authorizationUrl + "?" + query.Encode()
+		d.SetId(url)
+		if err := d.Set("url", url); err != nil {
+			return diag.FromErr(err)
+		}
+		return diag.FromErr(d.Set("openid_configuration_url", openidConfigurationUrl))
+	}
+
+	return &schema.Resource{
+		Description: "Builds the authorization URL of a verifier's OIDC client, without calling MATTR",
+		ReadContext: read,
+		Schema: map[string]*schema.Schema{
+			"verifier_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"client_id": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "ID of the mattr_verifier_client",
+			},
+			"redirect_uri": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Must be one of the client's redirect URIs",
+			},
+			"scope": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Scopes to request. Defaults to openid",
+			},
+			"response_type": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "code",
+				ValidateFunc: oneOf(responseTypes),
+			},
+			"state": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"nonce": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"code_challenge": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "PKCE code challenge derived from the relying party's code verifier",
+			},
+			"code_challenge_method": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "S256",
+				ValidateFunc: oneOf(codeChallengeMethods),
+				Description:  "How code_challenge was derived: S256 or plain. Only sent with code_challenge",
+			},
+			"url": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The authorization URL, with its query parameters encoded",
+			},
+			"openid_configuration_url": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "URL of the verifier's OpenID Provider metadata",
+			},
+		},
+	}
+}
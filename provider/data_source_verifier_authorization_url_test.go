@@ -0,0 +1,42 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestDataSourceVerifierAuthorizationUrl(t *testing.T) {
+	dataSource := dataSourceVerifierAuthorizationUrl()
+	readData := runRead(t, dataSource, map[string]interface{}{
+		"verifier_id":    "402c65eb-48e9-4a4c-b5e9-1ea615baccee",
+		"client_id":      "da9bb6e4-c9ae-4468-b6ac-72b90d6efd5d",
+		"redirect_uri":   "https://example.com/callback?from=login",
+		"scope":          []interface{}{"openid", "openid_credential_presentation"},
+		"state":          "af0ifjsldkj",
+		"code_challenge": "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM",
+	}, &TestClient{})
+
+	AssertEqual(t, "https://test.api/ext/oidc/v1/verifiers/402c65eb-48e9-4a4c-b5e9-1ea615baccee/authorize"+
+		"?client_id=da9bb6e4-c9ae-4468-b6ac-72b90d6efd5d"+
+		"&code_challenge=E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"+
+		"&code_challenge_method=S256"+
+		"&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback%3Ffrom%3Dlogin"+
+		"&response_type=code"+
+		"&scope=openid+openid_credential_presentation"+
+		"&state=af0ifjsldkj", readData.Get("url"), "URL should be encoded")
+	AssertEqual(t, "https://test.api/ext/oidc/v1/verifiers/402c65eb-48e9-4a4c-b5e9-1ea615baccee/.well-known/openid-configuration", readData.Get("openid_configuration_url"), "Discovery URL should match")
+}
+
+func TestDataSourceVerifierAuthorizationUrlDefaults(t *testing.T) {
+	dataSource := dataSourceVerifierAuthorizationUrl()
+	readData := runRead(t, dataSource, map[string]interface{}{
+		"verifier_id":  "402c65eb-48e9-4a4c-b5e9-1ea615baccee",
+		"client_id":    "da9bb6e4-c9ae-4468-b6ac-72b90d6efd5d",
+		"redirect_uri": "https://example.com/callback",
+	}, &TestClient{})
+
+	AssertEqual(t, "https://test.api/ext/oidc/v1/verifiers/402c65eb-48e9-4a4c-b5e9-1ea615baccee/authorize"+
+		"?client_id=da9bb6e4-c9ae-4468-b6ac-72b90d6efd5d"+
+		"&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback"+
+		"&response_type=code"+
+		"&scope=openid", readData.Get("url"), "Only the required parameters should be sent")
+}
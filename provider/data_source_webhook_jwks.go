@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"nz.antunovic/mattr-terraform-provider/api"
+	"nz.antunovic/mattr-terraform-provider/generator"
+)
+
+const webhookJwksPath = "/core/v1/webhooks/jwks"
+
+// jwkAttributes are the members of a JWK that are exposed as attributes.
+// Anything else is still available in `jwks`.
+var jwkAttributes = []string{"kid", "kty", "crv", "alg", "use", "x", "y"}
+
+// dataSourceWebhookJwks reads the public keys that MATTR signs webhook
+// requests with. The `webhook` package can verify signatures against `jwks`.
+func dataSourceWebhookJwks(client api.Client) *schema.Resource {
+	keySchema := map[string]*schema.Schema{}
+	for _, attribute := range jwkAttributes {
+		keySchema[attribute] = &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		}
+	}
+
+	webhookJwksSchema := map[string]*schema.Schema{
+		"jwks": &schema.Schema{
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The JWKS as JSON, for services that verify webhook requests",
+		},
+		"keys": &schema.Schema{
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: keySchema,
+			},
+		},
+	}
+
+	generator := generator.Generator{
+		Path:               webhookJwksPath,
+		Singleton:          true,
+		Client:             client,
+		Schema:             webhookJwksSchema,
+		ModifyResponseBody: convertWebhookJwksRes,
+		GetId: func(body *interface{}, response *interface{}) string {
+			return webhookJwksPath
+		},
+	}
+
+	dataSource := generator.GenDataSource()
+	return &dataSource
+}
+
+func convertWebhookJwksRes(body interface{}) (interface{}, error) {
+	bodyMap, ok := body.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Unexpected type for webhook JWKS: %T", body)
+	}
+
+	jwksJson, err := json.Marshal(bodyMap)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to serialise webhook JWKS: %s", err)
+	}
+
+	keyList, ok := bodyMap["keys"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Unexpected type for webhook JWKS 'keys' field: %T", bodyMap["keys"])
+	}
+
+	keys := make([]interface{}, 0)
+	for i, key := range keyList {
+		keyMap, ok := key.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Unexpected type for webhook JWKS key %d: %T", i, key)
+		}
+		jwk := map[string]interface{}{}
+		for _, attribute := range jwkAttributes {
+			if value, ok := keyMap[attribute].(string); ok {
+				jwk[attribute] = value
+			}
+		}
+		keys = append(keys, jwk)
+	}
+
+	return map[string]interface{}{
+		"jwks": string(jwksJson),
+		"keys": keys,
+	}, nil
+}
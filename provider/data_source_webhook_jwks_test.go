@@ -0,0 +1,39 @@
+package provider
+
+import (
+	"testing"
+
+	"nz.antunovic/mattr-terraform-provider/webhook"
+)
+
+func TestDataSourceWebhookJwks(t *testing.T) {
+	client := TestClient{
+		responses: map[string]interface{}{
+			"GET https://test.api/core/v1/webhooks/jwks": map[string]interface{}{
+				"keys": []interface{}{
+					map[string]interface{}{
+						"kid": "7N4VlvyX8Xj3ncu3XGpgZqTNoJRd3yE7Tf1CKpWrmKk",
+						"kty": "OKP",
+						"crv": "Ed25519",
+						"x":   "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo",
+					},
+				},
+			},
+		},
+	}
+
+	dataSource := dataSourceWebhookJwks(&client)
+	resourceData := runRead(t, dataSource, map[string]interface{}{}, &client)
+
+	AssertEqual(t, "/core/v1/webhooks/jwks", resourceData.Id(), "ID should be the JWKS path")
+	AssertEqual(t, "7N4VlvyX8Xj3ncu3XGpgZqTNoJRd3yE7Tf1CKpWrmKk", resourceData.Get("keys.0.kid"), "Key ID should match")
+	AssertEqual(t, "Ed25519", resourceData.Get("keys.0.crv"), "Curve should match")
+
+	jwks, err := webhook.ParseJWKS([]byte(resourceData.Get("jwks").(string)))
+	if err != nil {
+		t.Fatalf("Expected jwks to be usable for verification: %s", err)
+	}
+	if _, err := jwks.Keys[0].PublicKey(); err != nil {
+		t.Fatalf("Expected the key to be usable for verification: %s", err)
+	}
+}
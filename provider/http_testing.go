@@ -1,11 +1,15 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 	"log"
+
+	"nz.antunovic/mattr-terraform-provider/api"
 )
 
 type Request struct {
+	method  string
 	url     string
 	headers map[string]string
 	body    interface{}
@@ -14,33 +18,65 @@ type Request struct {
 type TestClient struct {
 	logs      []Request
 	responses map[string]interface{}
+	// headers are returned alongside the response for the same endpoint
+	headers map[string]map[string]string
+	// sequences are returned in order for repeated requests to the same
+	// endpoint, repeating the last one once they run out. They take
+	// precedence over responses, and errors in them are returned as errors.
+	sequences map[string][]interface{}
+	calls     map[string]int
 }
 
-func (client *TestClient) Post(url string, headers map[string]string, body interface{}) (interface{}, error) {
+func (client *TestClient) Post(ctx context.Context, url string, headers map[string]string, body interface{}) (*api.Response, error) {
 	return client.respond("POST", url, headers, body)
 }
 
-func (client *TestClient) Get(url string, headers map[string]string) (interface{}, error) {
+func (client *TestClient) Get(ctx context.Context, url string, headers map[string]string) (*api.Response, error) {
 	return client.respond("GET", url, headers, nil)
 }
 
-func (client *TestClient) Put(url string, headers map[string]string, body interface{}) (interface{}, error) {
+func (client *TestClient) Put(ctx context.Context, url string, headers map[string]string, body interface{}) (*api.Response, error) {
 	return client.respond("PUT", url, headers, body)
 }
 
-func (client *TestClient) Delete(url string, headers map[string]string) error {
+func (client *TestClient) Delete(ctx context.Context, url string, headers map[string]string) error {
 	_, err := client.respond("DELETE", url, headers, nil)
 	return err
 }
 
-func (client *TestClient) respond(method string, url string, headers map[string]string, body interface{}) (interface{}, error) {
+func (client *TestClient) respond(method string, url string, headers map[string]string, body interface{}) (*api.Response, error) {
 	endpoint := fmt.Sprintf("%s %s", method, url)
+	client.logs = append(client.logs, Request{
+		method:  method,
+		url:     url,
+		headers: headers,
+		body:    body,
+	})
 	log.Printf("Locating response for %s", endpoint)
 	response, ok := client.responses[endpoint]
+	if sequence := client.sequences[endpoint]; len(sequence) != 0 {
+		if client.calls == nil {
+			client.calls = make(map[string]int)
+		}
+		call := client.calls[endpoint]
+		client.calls[endpoint]++
+		if call >= len(sequence) {
+			call = len(sequence) - 1
+		}
+		response, ok = sequence[call], true
+	}
 	if !ok {
 		log.Printf("Failed to find response for %s", endpoint)
 		return nil, fmt.Errorf("Unable to find response for %s", endpoint)
 	}
+	if err, ok := response.(error); ok {
+		log.Printf("Returning error for %s: %s", endpoint, err)
+		return nil, err
+	}
 	log.Printf("Successfully located response for %s", endpoint)
-	return response, nil
+	return &api.Response{
+		StatusCode: 200,
+		Headers:    client.headers[endpoint],
+		Body:       response,
+	}, nil
 }
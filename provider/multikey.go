@@ -0,0 +1,76 @@
+package provider
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// multicodecPrefixes are the varint encoded multicodec codes for the key types
+// published as publicKeyBase58, see
+// https://github.com/multiformats/multicodec/blob/master/table.csv
+var multicodecPrefixes = map[string][]byte{
+	"Ed25519VerificationKey2018":        {0xed, 0x01},
+	"X25519KeyAgreementKey2019":         {0xec, 0x01},
+	"Bls12381G2Key2020":                 {0xeb, 0x01},
+	"EcdsaSecp256k1VerificationKey2019": {0xe7, 0x01},
+}
+
+// base58Multikey converts a raw base58 public key of the given verification
+// method type into Multikey form: the key prefixed with its multicodec code,
+// base58btc encoded and prefixed with "z" for multibase
+func base58Multikey(methodType string, publicKeyBase58 string) (string, error) {
+	prefix, ok := multicodecPrefixes[methodType]
+	if !ok {
+		return "", fmt.Errorf("Unsupported verification method type %s", methodType)
+	}
+
+	publicKey, err := base58Decode(publicKeyBase58)
+	if err != nil {
+		return "", err
+	}
+
+	return "z" + base58Encode(append(append([]byte{}, prefix...), publicKey...)), nil
+}
+
+func base58Decode(encoded string) ([]byte, error) {
+	value := new(big.Int)
+	radix := big.NewInt(58)
+	for _, c := range encoded {
+		digit := strings.IndexRune(base58Alphabet, c)
+		if digit < 0 {
+			return nil, fmt.Errorf("Invalid base58 character %q", c)
+		}
+		value.Mul(value, radix)
+		value.Add(value, big.NewInt(int64(digit)))
+	}
+
+	// leading zero bytes are encoded as leading 1s
+	zeros := len(encoded) - len(strings.TrimLeft(encoded, "1"))
+	return append(make([]byte, zeros), value.Bytes()...), nil
+}
+
+func base58Encode(data []byte) string {
+	value := new(big.Int).SetBytes(data)
+	radix := big.NewInt(58)
+	digit := new(big.Int)
+
+	encoded := make([]byte, 0, len(data)*138/100+1)
+	for value.Sign() > 0 {
+		value.DivMod(value, radix, digit)
+		encoded = append(encoded, base58Alphabet[digit.Int64()])
+	}
+	for _, b := range data {
+		if b != 0 {
+			break
+		}
+		encoded = append(encoded, '1')
+	}
+
+	for i, j := 0, len(encoded)-1; i < j; i, j = i+1, j-1 {
+		encoded[i], encoded[j] = encoded[j], encoded[i]
+	}
+	return string(encoded)
+}
@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"nz.antunovic/mattr-terraform-provider/api"
+)
+
+// The tenant's OIDC issuance configuration is one document, parts of which
+// are managed by separate resources. PUT replaces the whole document, so each
+// resource reads the configuration and sends it back with only its own
+// property changed.
+const openidConfigurationPath = "/core/v1/openid/configuration"
+
+// openidConfigurationMutex stops resources, which Terraform applies in
+// parallel, from overwriting each other's changes between reading the
+// configuration and sending it back
+var openidConfigurationMutex sync.Mutex
+
+func getOpenidConfiguration(ctx context.Context, client api.Client, m interface{}) (map[string]interface{}, error) {
+	providerApi := m.(api.ProviderConfig).Api
+	url, err := providerApi.GetUrl(openidConfigurationPath)
+	if err != nil {
+		return nil, err
+	}
+	headers, err := providerApi.AuthHeaders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := client.Get(ctx, url, headers)
+	if err != nil {
+		return nil, err
+	}
+	configuration, ok := response.Body.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Unexpected type for %s: %T", openidConfigurationPath, response.Body)
+	}
+	return configuration, nil
+}
+
+// putOpenidConfigurationProperty sets one property of the configuration, or
+// removes it if value is nil, and returns the updated configuration
+func putOpenidConfigurationProperty(ctx context.Context, client api.Client, m interface{}, property string, value interface{}) (map[string]interface{}, error) {
+	openidConfigurationMutex.Lock()
+	defer openidConfigurationMutex.Unlock()
+
+	configuration, err := getOpenidConfiguration(ctx, client, m)
+	if err != nil {
+		return nil, err
+	}
+
+	// other properties, including the interaction hook and its secret, are
+	// sent back as read
+	currentInteractionHook := configuration["interactionHook"]
+	if value == nil {
+		delete(configuration, property)
+	} else {
+		configuration[property] = value
+	}
+	keepInteractionHookSecret(configuration, currentInteractionHook)
+
+	providerApi := m.(api.ProviderConfig).Api
+	url, err := providerApi.GetUrl(openidConfigurationPath)
+	if err != nil {
+		return nil, err
+	}
+	headers, err := providerApi.AuthHeaders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := client.Put(ctx, url, headers, configuration)
+	if err != nil {
+		return nil, err
+	}
+	configuration, ok := response.Body.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Unexpected type for %s: %T", openidConfigurationPath, response.Body)
+	}
+	return configuration, nil
+}
+
+// keepInteractionHookSecret sends the interaction hook's current secret back
+// when the hook is written without one, e.g. after import, as MATTR generates
+// a new secret whenever the hook is sent without one
+func keepInteractionHookSecret(configuration map[string]interface{}, current interface{}) {
+	interactionHook, ok := configuration["interactionHook"].(map[string]interface{})
+	if !ok || interactionHook["secret"] != nil {
+		return
+	}
+	if currentHook, ok := current.(map[string]interface{}); ok && currentHook["secret"] != nil {
+		interactionHook["secret"] = currentHook["secret"]
+	}
+}
@@ -35,20 +35,40 @@ func Provider() *schema.Provider {
 			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"mattr_did":                                  resourceDid(),
+			"mattr_did":                                  resourceDid(&client),
 			"mattr_webhook":                              resourceWebhook(&client),
-			"mattr_issuer":                               resourceIssuer(),
+			"mattr_issuer":                               resourceIssuer(&client),
 			"mattr_credential_web":                       resourceCredentialConfig(),
+			"mattr_credential_mobile":                    resourceCredentialMobile(&client),
+			"mattr_iaca":                                 resourceIaca(&client),
+			"mattr_document_signer_certificate":          resourceDocumentSignerCertificate(&client),
+			"mattr_trusted_issuer":                       resourceTrustedIssuer(&client),
+			"mattr_verifier_application":                 resourceVerifierApplication(&client),
+			"mattr_mobile_presentation_template":         resourceMobilePresentationTemplate(&client),
+			"mattr_interaction_hook":                     resourceInteractionHook(&client),
+			"mattr_issuer_display":                       resourceIssuerDisplay(&client),
+			"mattr_ecosystem":                            resourceEcosystem(&client),
+			"mattr_ecosystem_participant":                resourceEcosystemParticipant(&client),
+			"mattr_ecosystem_credential_type":            resourceEcosystemCredentialType(&client),
 			"mattr_claim_source":                         resourceClaimSource(),
 			"mattr_authentication_provider":              resourceAuthentication(),
 			"mattr_issuer_client":                        resourceIssuerClient(),
 			"mattr_verifier":                             resourceVerifier(),
 			"mattr_verifier_client":                      resourceVerifierClient(&client),
-			"mattr_custom_domain":                        resourceCustomDomain(),
+			"mattr_custom_domain":                        resourceCustomDomain(&client),
+			"mattr_custom_domain_verification":           resourceCustomDomainVerification(&client),
 			"mattr_compact_credential_template":          resourceCompactCredentialTemplate(),
 			"mattr_semantic_compact_credential_template": resourceSemanticCompactCredentialTemplate(),
 			"mattr_credential_offer":                     resourceCredentialOffer(),
-			"mattr_presentation":                         resourcePresentation(),
+			"mattr_presentation":                         resourcePresentation(&client),
+			"mattr_credential_revocation":                resourceCredentialRevocation(&client),
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"mattr_did_resolution":             dataSourceDidResolution(&client),
+			"mattr_webhook_jwks":               dataSourceWebhookJwks(&client),
+			"mattr_verifier_authorization_url": dataSourceVerifierAuthorizationUrl(),
+			"mattr_credential_preview":         dataSourceCredentialPreview(),
+			"mattr_revocation_list":            dataSourceRevocationList(&client),
 		},
 		ConfigureFunc: ProviderConfigure,
 	}
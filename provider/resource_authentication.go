@@ -26,8 +26,9 @@ func resourceAuthentication() *schema.Resource {
 			Required: true,
 		},
 		"token_endpoint_auth_method": &schema.Schema{
-			Type:     schema.TypeString,
-			Optional: true,
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: oneOf(tokenEndpointAuthMethods),
 		},
 		"claims_source": &schema.Schema{
 			Type:     schema.TypeString,
@@ -55,9 +56,10 @@ func resourceAuthentication() *schema.Resource {
 	}
 
 	providerGen := generator.Generator{
-		Path:   "/core/v1/users/authenticationproviders",
-		Schema: schema,
-		Client: &api.HttpClient{},
+		Path:                  "/core/v1/users/authenticationproviders",
+		Schema:                schema,
+		Client:                &api.HttpClient{},
+		OptimisticConcurrency: true,
 	}
 
 	provider := providerGen.GenResource()
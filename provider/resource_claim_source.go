@@ -8,6 +8,10 @@ import (
 	"nz.antunovic/mattr-terraform-provider/generator"
 )
 
+// claim sources are sent the user's claims and the configuration of the
+// credential
+var claimSourceClaimRoots = []string{"claims", "credentialConfiguration"}
+
 func resourceClaimSource() *schema.Resource {
 	claimSourceSchema := map[string]*schema.Schema{
 		"name": &schema.Schema{
@@ -19,8 +23,9 @@ func resourceClaimSource() *schema.Resource {
 			Required: true,
 		},
 		"authorization_type": &schema.Schema{
-			Type:     schema.TypeString,
-			Required: true,
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: oneOf(claimSourceAuthorizationTypes),
 		},
 		"authorization_value": &schema.Schema{
 			Type:     schema.TypeString,
@@ -36,8 +41,10 @@ func resourceClaimSource() *schema.Resource {
 						Required: true,
 					},
 					"map_from": &schema.Schema{
-						Type:     schema.TypeString,
-						Required: true,
+						Type:         schema.TypeString,
+						Required:     true,
+						ValidateFunc: validateClaimPath(claimSourceClaimRoots),
+						Description:  "Path of the claim to map from, e.g. claims.given_name",
 					},
 					"default_value": &schema.Schema{
 						Type:     schema.TypeString,
@@ -49,11 +56,12 @@ func resourceClaimSource() *schema.Resource {
 	}
 
 	claimSourceGenerator := generator.Generator{
-		Path:               "/core/v1/claimsources",
-		Client:             &api.HttpClient{},
-		Schema:             claimSourceSchema,
-		ModifyRequestBody:  convertReqParamsBody,
-		ModifyResponseBody: convertResParamsBody,
+		Path:                  "/core/v1/claimsources",
+		Client:                &api.HttpClient{},
+		Schema:                claimSourceSchema,
+		ModifyRequestBody:     convertReqParamsBody,
+		ModifyResponseBody:    convertResParamsBody,
+		OptimisticConcurrency: true,
 	}
 
 	resource := claimSourceGenerator.GenResource()
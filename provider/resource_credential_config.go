@@ -2,6 +2,8 @@ package provider
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"nz.antunovic/mattr-terraform-provider/api"
@@ -45,8 +47,11 @@ func resourceCredentialConfig() *schema.Resource {
 			Required: true,
 		},
 		"proof_type": &schema.Schema{
-			Type:     schema.TypeSet,
-			Elem:     &schema.Schema{Type: schema.TypeString},
+			Type: schema.TypeSet,
+			Elem: &schema.Schema{
+				Type:         schema.TypeString,
+				ValidateFunc: oneOf(proofTypes),
+			},
 			Optional: true,
 		},
 		"background_color": &schema.Schema{
@@ -57,30 +62,7 @@ func resourceCredentialConfig() *schema.Resource {
 			Type:     schema.TypeString,
 			Optional: true,
 		},
-		"claim_mapping": &schema.Schema{
-			Type:     schema.TypeSet,
-			Required: true,
-			Elem: &schema.Resource{
-				Schema: map[string]*schema.Schema{
-					"name": &schema.Schema{
-						Type:     schema.TypeString,
-						Required: true,
-					},
-					"map_from": &schema.Schema{
-						Type:     schema.TypeString,
-						Optional: true,
-					},
-					"default_value": &schema.Schema{
-						Type:     schema.TypeString,
-						Optional: true,
-					},
-					"required": &schema.Schema{
-						Type:     schema.TypeBool,
-						Optional: true,
-					},
-				},
-			},
-		},
+		"claim_mapping": claimMappingSchema(),
 		"persist": &schema.Schema{
 			Type:     schema.TypeBool,
 			Optional: true,
@@ -97,34 +79,9 @@ func resourceCredentialConfig() *schema.Resource {
 			Type:     schema.TypeString,
 			Optional: true,
 		},
-		"years": &schema.Schema{
-			Type:     schema.TypeInt,
-			Optional: true,
-		},
-		"months": &schema.Schema{
-			Type:     schema.TypeInt,
-			Optional: true,
-		},
-		"weeks": &schema.Schema{
-			Type:     schema.TypeInt,
-			Optional: true,
-		},
-		"days": &schema.Schema{
-			Type:     schema.TypeInt,
-			Optional: true,
-		},
-		"hours": &schema.Schema{
-			Type:     schema.TypeInt,
-			Optional: true,
-		},
-		"minutes": &schema.Schema{
-			Type:     schema.TypeInt,
-			Optional: true,
-		},
-		"seconds": &schema.Schema{
-			Type:     schema.TypeInt,
-			Optional: true,
-		},
+	}
+	for unit, unitSchema := range expiresInSchema() {
+		credentialConfigSchema[unit] = unitSchema
 	}
 
 	claimSourceGenerator := generator.Generator{
@@ -133,6 +90,8 @@ func resourceCredentialConfig() *schema.Resource {
 		Schema:             credentialConfigSchema,
 		ModifyRequestBody:  convertCredentialConfigReq,
 		ModifyResponseBody: convertCredentialConfigRes,
+		// branding is often tweaked in the MATTR portal
+		OptimisticConcurrency: true,
 	}
 
 	provider := claimSourceGenerator.GenResource()
@@ -179,22 +138,7 @@ func convertCredentialConfigReq(body interface{}) (interface{}, error) {
 	delete(bodyMap, "claimMapping")
 
 	// expires
-	expiresMap := make(map[string]interface{}, 7)
-	expiresMap["years"] = bodyMap["years"]
-	expiresMap["months"] = bodyMap["months"]
-	expiresMap["weeks"] = bodyMap["weeks"]
-	expiresMap["days"] = bodyMap["days"]
-	expiresMap["hours"] = bodyMap["hours"]
-	expiresMap["minutes"] = bodyMap["minutes"]
-	expiresMap["seconds"] = bodyMap["seconds"]
-	bodyMap["expiresIn"] = expiresMap
-	delete(bodyMap, "years")
-	delete(bodyMap, "months")
-	delete(bodyMap, "weeks")
-	delete(bodyMap, "days")
-	delete(bodyMap, "hours")
-	delete(bodyMap, "minutes")
-	delete(bodyMap, "seconds")
+	convertExpiresInReq(bodyMap)
 
 	return bodyMap, nil
 }
@@ -240,18 +184,112 @@ func convertCredentialConfigRes(body interface{}) (interface{}, error) {
 	delete(bodyMap, "claimMappings")
 
 	// expires
+	if err := convertExpiresInRes(bodyMap); err != nil {
+		return nil, err
+	}
+
+	return bodyMap, nil
+}
+
+// expiresInUnits are the fields of `expiresIn`, which credential
+// configurations flatten into top-level attributes
+var expiresInUnits = []string{"years", "months", "weeks", "days", "hours", "minutes", "seconds"}
+
+func expiresInSchema() map[string]*schema.Schema {
+	expiresIn := make(map[string]*schema.Schema, len(expiresInUnits))
+	for _, unit := range expiresInUnits {
+		expiresIn[unit] = &schema.Schema{
+			Type:     schema.TypeInt,
+			Optional: true,
+		}
+	}
+	return expiresIn
+}
+
+func convertExpiresInReq(bodyMap map[string]interface{}) {
+	expiresMap := make(map[string]interface{}, len(expiresInUnits))
+	for _, unit := range expiresInUnits {
+		expiresMap[unit] = bodyMap[unit]
+		delete(bodyMap, unit)
+	}
+	bodyMap["expiresIn"] = expiresMap
+}
+
+func convertExpiresInRes(bodyMap map[string]interface{}) error {
 	expiresMap, ok := bodyMap["expiresIn"].(map[string]interface{})
 	if !ok {
-		return nil, fmt.Errorf("Unexpected type for response body field 'expiresIn': %T", bodyMap["expiresIn"])
+		return fmt.Errorf("Unexpected type for response body field 'expiresIn': %T", bodyMap["expiresIn"])
+	}
+	for _, unit := range expiresInUnits {
+		bodyMap[unit] = expiresMap[unit]
 	}
-	bodyMap["years"] = expiresMap["years"]
-	bodyMap["months"] = expiresMap["months"]
-	bodyMap["weeks"] = expiresMap["weeks"]
-	bodyMap["days"] = expiresMap["days"]
-	bodyMap["hours"] = expiresMap["hours"]
-	bodyMap["minutes"] = expiresMap["minutes"]
-	bodyMap["seconds"] = expiresMap["seconds"]
 	delete(bodyMap, "expiresIn")
+	return nil
+}
 
-	return bodyMap, nil
+// claimMappingSchema maps claims of the user into a web credential, and is
+// shared with the credential preview so that both accept the same mappings
+func claimMappingSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeSet,
+		Required: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+				"map_from": &schema.Schema{
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validateClaimPath(credentialClaimRoots),
+					Description:  "Path of the claim to map from, e.g. claims.given_name",
+				},
+				"default_value": &schema.Schema{
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+				"required": &schema.Schema{
+					Type:     schema.TypeBool,
+					Optional: true,
+				},
+			},
+		},
+	}
+}
+
+var (
+	// credentials map claims from the user's claims, which include those
+	// from claim sources
+	credentialClaimRoots = []string{"claims"}
+
+	// claim names can be URLs or URNs, so a segment can be any name without
+	// whitespace or brackets, followed by list indices like [0]
+	claimPathSegment = regexp.MustCompile(`^[^\s\[\]]+(\[[0-9]+\])*$`)
+)
+
+// validateClaimPath checks the syntax of a map_from expression, a path like
+// claims.address.locality or claims.emails[0] that starts at one of roots
+func validateClaimPath(roots []string) schema.SchemaValidateFunc {
+	return func(value interface{}, key string) ([]string, []error) {
+		path, ok := value.(string)
+		if !ok {
+			return nil, []error{fmt.Errorf("expected type of %s to be string", key)}
+		}
+
+		segments := strings.Split(path, ".")
+		if !containsString(roots, segments[0]) {
+			return nil, []error{fmt.Errorf("%s must start with one of %s, got %q", key, strings.Join(roots, ", "), path)}
+		}
+		if len(segments) == 1 {
+			return nil, []error{fmt.Errorf("%s must name a claim within %s, e.g. %s.given_name", key, segments[0], segments[0])}
+		}
+		for _, segment := range segments[1:] {
+			if !claimPathSegment.MatchString(segment) {
+				return nil, []error{fmt.Errorf("%s has an invalid segment %q in %q", key, segment, path)}
+			}
+		}
+
+		return nil, nil
+	}
 }
@@ -0,0 +1,21 @@
+package provider
+
+import "testing"
+
+func TestValidateClaimPath(t *testing.T) {
+	validate := validateClaimPath(credentialClaimRoots)
+	for _, path := range []string{"claims.given_name", "claims.address.locality", "claims.emails[0]", "claims.matrix[0][1]", "claims.emails[0].value", "claims.$ref", "claims.https://schema.org/givenName", "claims.urn:example:employee-id"} {
+		if _, errs := validate(path, "map_from"); len(errs) != 0 {
+			t.Errorf("Expected %q to be valid: %v", path, errs)
+		}
+	}
+	for _, path := range []string{"claim.given_name", "claims", "claims.", "claims..given_name", "claims.given name", "given_name", "claims.emails[", "claims.emails[x]", "claims.a]b", "claims.emails[0", "claims.[0]"} {
+		if _, errs := validate(path, "map_from"); len(errs) == 0 {
+			t.Errorf("Expected %q to be invalid", path)
+		}
+	}
+
+	if _, errs := validateClaimPath(claimSourceClaimRoots)("credentialConfiguration.type", "map_from"); len(errs) != 0 {
+		t.Errorf("Claim sources should accept the credential configuration: %v", errs)
+	}
+}
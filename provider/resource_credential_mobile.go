@@ -0,0 +1,208 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"nz.antunovic/mattr-terraform-provider/api"
+	"nz.antunovic/mattr-terraform-provider/generator"
+)
+
+const mobileCredentialConfigPath = "/v2/credentials/mobile/configurations"
+
+// resourceCredentialMobile manages the configuration of ISO 18013-5 mDocs
+// issued over OpenID4VCI
+func resourceCredentialMobile(client api.Client) *schema.Resource {
+	credentialMobileSchema := map[string]*schema.Schema{
+		"name": &schema.Schema{
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"description": &schema.Schema{
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"doctype": &schema.Schema{
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "The mDoc's document type, e.g. org.iso.18013.5.1.mDL",
+		},
+		"background_color": &schema.Schema{
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"issuer_logo_url": &schema.Schema{
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"issuer_icon_url": &schema.Schema{
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"claim_mapping": &schema.Schema{
+			Type:     schema.TypeSet,
+			Required: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"namespace": &schema.Schema{
+						Type:        schema.TypeString,
+						Required:    true,
+						Description: "Namespace the claim belongs to, e.g. org.iso.18013.5.1",
+					},
+					"name": &schema.Schema{
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"map_from": &schema.Schema{
+						Type:         schema.TypeString,
+						Optional:     true,
+						ValidateFunc: validateClaimPath(credentialClaimRoots),
+						Description:  "Path of the claim to map from, e.g. claims.given_name",
+					},
+					"default_value": &schema.Schema{
+						Type:     schema.TypeString,
+						Optional: true,
+					},
+					"required": &schema.Schema{
+						Type:     schema.TypeBool,
+						Optional: true,
+					},
+					"type": &schema.Schema{
+						Type:         schema.TypeString,
+						Optional:     true,
+						ValidateFunc: oneOf(mdocClaimTypes),
+						Description:  "Type the claim is encoded as in the mDoc",
+					},
+				},
+			},
+		},
+		"include_status": &schema.Schema{
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Include a status list entry, so that issued mDocs can be revoked",
+		},
+	}
+	for unit, unitSchema := range expiresInSchema() {
+		credentialMobileSchema[unit] = unitSchema
+	}
+
+	generator := generator.Generator{
+		Path:                  mobileCredentialConfigPath,
+		Client:                client,
+		Schema:                credentialMobileSchema,
+		ModifyRequestBody:     convertCredentialMobileReq,
+		ModifyResponseBody:    convertCredentialMobileRes,
+		OptimisticConcurrency: true,
+	}
+
+	resource := generator.GenResource()
+	return &resource
+}
+
+func convertCredentialMobileReq(body interface{}) (interface{}, error) {
+	bodyMap, ok := body.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Unexpected type for %s: %T", mobileCredentialConfigPath, body)
+	}
+
+	bodyMap["type"] = bodyMap["doctype"]
+	delete(bodyMap, "doctype")
+
+	// branding
+	branding := make(map[string]interface{})
+	branding["backgroundColor"] = bodyMap["backgroundColor"]
+	if logoUrl, ok := bodyMap["issuerLogoUrl"]; ok {
+		branding["issuerLogo"] = map[string]interface{}{"url": logoUrl}
+	}
+	if iconUrl, ok := bodyMap["issuerIconUrl"]; ok {
+		branding["issuerIcon"] = map[string]interface{}{"url": iconUrl}
+	}
+	bodyMap["branding"] = branding
+	delete(bodyMap, "backgroundColor")
+	delete(bodyMap, "issuerLogoUrl")
+	delete(bodyMap, "issuerIconUrl")
+
+	// claim mappings are grouped by namespace
+	claimMappingList, _ := bodyMap["claimMapping"].([]interface{})
+	claimMappingApi := make(map[string]interface{})
+	for _, claimMapping := range claimMappingList {
+		claimMappingMap, ok := claimMapping.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Unexpected type for %s `claim_mapping`: %T", mobileCredentialConfigPath, claimMapping)
+		}
+		namespace, _ := claimMappingMap["namespace"].(string)
+		name, _ := claimMappingMap["name"].(string)
+		delete(claimMappingMap, "namespace")
+		delete(claimMappingMap, "name")
+
+		namespaceMap, ok := claimMappingApi[namespace].(map[string]interface{})
+		if !ok {
+			namespaceMap = make(map[string]interface{})
+			claimMappingApi[namespace] = namespaceMap
+		}
+		if _, exists := namespaceMap[name]; exists {
+			return nil, fmt.Errorf("Claim %q is mapped more than once in namespace %q", name, namespace)
+		}
+		namespaceMap[name] = claimMappingMap
+	}
+	bodyMap["claimMappings"] = claimMappingApi
+	delete(bodyMap, "claimMapping")
+
+	// expires
+	convertExpiresInReq(bodyMap)
+
+	return bodyMap, nil
+}
+
+func convertCredentialMobileRes(body interface{}) (interface{}, error) {
+	bodyMap, ok := body.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Unexpected type for response body: %T", body)
+	}
+
+	bodyMap["doctype"] = bodyMap["type"]
+	delete(bodyMap, "type")
+
+	// branding
+	if branding, ok := bodyMap["branding"].(map[string]interface{}); ok {
+		bodyMap["backgroundColor"] = branding["backgroundColor"]
+		if logo, ok := branding["issuerLogo"].(map[string]interface{}); ok {
+			bodyMap["issuerLogoUrl"] = logo["url"]
+		}
+		if icon, ok := branding["issuerIcon"].(map[string]interface{}); ok {
+			bodyMap["issuerIconUrl"] = icon["url"]
+		}
+	}
+	delete(bodyMap, "branding")
+
+	// claim mappings
+	claimMappingApi, ok := bodyMap["claimMappings"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Unexpected type for response body field 'claimMappings': %T", bodyMap["claimMappings"])
+	}
+	claimMappingList := make([]interface{}, 0)
+	for namespace, namespaceMappings := range claimMappingApi {
+		namespaceMap, ok := namespaceMappings.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Unexpected type for response body field 'claimMappings'[%s]: %T", namespace, namespaceMappings)
+		}
+		for name, claimMappingMap := range namespaceMap {
+			claimMapping, ok := claimMappingMap.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("Unexpected type for response body field 'claimMappings'[%s][%s]: %T", namespace, name, claimMappingMap)
+			}
+			claimMapping["namespace"] = namespace
+			claimMapping["name"] = name
+			claimMappingList = append(claimMappingList, claimMapping)
+		}
+	}
+	bodyMap["claimMapping"] = claimMappingList
+	delete(bodyMap, "claimMappings")
+
+	// expires
+	if err := convertExpiresInRes(bodyMap); err != nil {
+		return nil, err
+	}
+
+	return bodyMap, nil
+}
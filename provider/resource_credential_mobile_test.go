@@ -0,0 +1,96 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestResourceCredentialMobileCreate(t *testing.T) {
+	client := TestClient{
+		responses: map[string]interface{}{
+			"POST https://test.api/v2/credentials/mobile/configurations": map[string]interface{}{
+				"id":   "8c5c2ba8-2a5b-4d33-a8b8-0d3d3d1f3c0b",
+				"name": "Driver licence",
+				"type": "org.iso.18013.5.1.mDL",
+				"branding": map[string]interface{}{
+					"backgroundColor": "#1B4E9C",
+					"issuerLogo": map[string]interface{}{
+						"url": "https://example.com/logo.png",
+					},
+				},
+				"claimMappings": map[string]interface{}{
+					"org.iso.18013.5.1": map[string]interface{}{
+						"family_name": map[string]interface{}{
+							"mapFrom":  "claims.family_name",
+							"required": true,
+						},
+					},
+					"org.iso.18013.5.1.aamva": map[string]interface{}{
+						"DHS_compliance": map[string]interface{}{
+							"defaultValue": "F",
+							"type":         "string",
+						},
+					},
+				},
+				"expiresIn": map[string]interface{}{
+					"years": 5.0,
+				},
+				"includeStatus": true,
+			},
+		},
+	}
+
+	createData := map[string]interface{}{
+		"name":             "Driver licence",
+		"doctype":          "org.iso.18013.5.1.mDL",
+		"background_color": "#1B4E9C",
+		"issuer_logo_url":  "https://example.com/logo.png",
+		"claim_mapping": []interface{}{
+			map[string]interface{}{
+				"namespace": "org.iso.18013.5.1",
+				"name":      "family_name",
+				"map_from":  "claims.family_name",
+				"required":  true,
+			},
+			map[string]interface{}{
+				"namespace":     "org.iso.18013.5.1.aamva",
+				"name":          "DHS_compliance",
+				"default_value": "F",
+				"type":          "string",
+			},
+		},
+		"years":          5,
+		"include_status": true,
+	}
+
+	resource := resourceCredentialMobile(&client)
+	resourceData := runCreate(t, resource, createData, &client)
+
+	body := client.logs[0].body.(map[string]interface{})
+	AssertEqual(t, "org.iso.18013.5.1.mDL", body["type"], "Doctype should be sent as type")
+	AssertEqual(t, map[string]interface{}{"url": "https://example.com/logo.png"}, body["branding"].(map[string]interface{})["issuerLogo"], "Logo should be sent in branding")
+	claimMappings := body["claimMappings"].(map[string]interface{})
+	AssertEqual(t, "claims.family_name", claimMappings["org.iso.18013.5.1"].(map[string]interface{})["family_name"].(map[string]interface{})["mapFrom"], "Claims should be grouped by namespace")
+	AssertEqual(t, "F", claimMappings["org.iso.18013.5.1.aamva"].(map[string]interface{})["DHS_compliance"].(map[string]interface{})["defaultValue"], "Claims should be grouped by namespace")
+	AssertEqual(t, 5, body["expiresIn"].(map[string]interface{})["years"], "Validity should be sent as expiresIn")
+
+	AssertEqual(t, "8c5c2ba8-2a5b-4d33-a8b8-0d3d3d1f3c0b", resourceData.Id(), "ID should match")
+	AssertEqual(t, "org.iso.18013.5.1.mDL", resourceData.Get("doctype"), "Doctype should match")
+	AssertEqual(t, "https://example.com/logo.png", resourceData.Get("issuer_logo_url"), "Logo should match")
+	AssertEqual(t, 2, resourceData.Get("claim_mapping").(*schema.Set).Len(), "Both claim mappings should be read")
+	AssertEqual(t, 5, resourceData.Get("years"), "Validity should match")
+}
+
+func TestResourceCredentialMobileRejectsDuplicateClaims(t *testing.T) {
+	_, err := convertCredentialMobileReq(map[string]interface{}{
+		"doctype": "org.iso.18013.5.1.mDL",
+		"claimMapping": []interface{}{
+			map[string]interface{}{"namespace": "org.iso.18013.5.1", "name": "family_name", "mapFrom": "claims.family_name"},
+			map[string]interface{}{"namespace": "org.iso.18013.5.1", "name": "family_name", "mapFrom": "claims.surname"},
+		},
+	})
+	if err == nil {
+		t.Fatal("Expected an error for a claim mapped twice in the same namespace")
+	}
+}
@@ -1,8 +1,10 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	_ "github.com/motemen/go-loghttp/global"
 	"nz.antunovic/mattr-terraform-provider/api"
@@ -44,10 +46,10 @@ func resourceCredentialOffer() *schema.Resource {
 	}
 
 	resource := generator.GenResource()
-	resource.Read = func(*schema.ResourceData, interface{}) error {
+	resource.ReadContext = func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
 		return nil
 	}
-	resource.Delete = func(*schema.ResourceData, interface{}) error {
+	resource.DeleteContext = func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
 		return nil
 	}
 
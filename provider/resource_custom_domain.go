@@ -1,15 +1,26 @@
 package provider
 
 import (
+	"context"
 	"fmt"
+	"net/url"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"nz.antunovic/mattr-terraform-provider/api"
 	"nz.antunovic/mattr-terraform-provider/generator"
 )
 
-func resourceCustomDomain() *schema.Resource {
-	schema := map[string]*schema.Schema{
+// customDomainVerificationLabel is the default label prefixed to the custom
+// domain to name the TXT record holding its verification token. The record
+// can't share the domain's own name, which already has a CNAME record
+// (RFC 1034 section 3.6.2). MATTR doesn't return the record name, so the label
+// can be overridden to match the one shown in the MATTR Portal.
+const customDomainVerificationLabel = "_mattr-verification"
+
+func resourceCustomDomain(client api.Client) *schema.Resource {
+	customDomainSchema := map[string]*schema.Schema{
 		"name": &schema.Schema{
 			Type:     schema.TypeString,
 			Required: true,
@@ -39,9 +50,52 @@ func resourceCustomDomain() *schema.Resource {
 			Type:     schema.TypeString,
 			Computed: true,
 		},
+		"verification_record_label": &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      customDomainVerificationLabel,
+			ValidateFunc: validation.StringIsNotWhiteSpace,
+			Description:  fmt.Sprintf("Label prefixed to the domain to name the TXT record MATTR checks when verifying the domain, `%s` by default. Only used to compute the DNS records, it isn't sent to MATTR.", customDomainVerificationLabel),
+		},
+		"dns_txt_record_name": &schema.Schema{
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Name of the TXT record MATTR checks when verifying the domain, which is the domain prefixed with `verification_record_label`",
+		},
+		"dns_txt_record_value": &schema.Schema{
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Value of the TXT record MATTR checks when verifying the domain",
+		},
+		"cname_target": &schema.Schema{
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Host name of the MATTR tenant, which the domain's CNAME record should point to",
+		},
+		"dns_records": &schema.Schema{
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: "All the DNS records needed for the custom domain",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"type": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"name": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"value": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+				},
+			},
+		},
 	}
 
-	path := "/core/v1/config/domain"
+	path := customDomainPath
 
 	modifyCustomDomainRes := func(response interface{}) (interface{}, error) {
 		responseMap, ok := response.(map[string]interface{})
@@ -52,14 +106,70 @@ func resourceCustomDomain() *schema.Resource {
 		return responseMap, nil
 	}
 
+	modifyCustomDomainReq := func(request interface{}) (interface{}, error) {
+		requestMap, ok := request.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Unexpected type for %s request: %T", path, request)
+		}
+		delete(requestMap, "verificationRecordLabel")
+		return requestMap, nil
+	}
+
 	custDomain := generator.Generator{
 		Path:               path,
-		Client:             &api.HttpClient{},
-		Schema:             schema,
+		Client:             client,
+		Schema:             customDomainSchema,
 		Singleton:          true,
+		ModifyRequestBody:  modifyCustomDomainReq,
 		ModifyResponseBody: modifyCustomDomainRes,
+		// the CNAME target depends on the tenant URL in the provider config
+		AfterOperation: setCustomDomainDnsRecords,
+		CustomizeDiff: customdiff.All(
+			customdiff.ComputedIf("dns_txt_record_name", verificationLabelChanged),
+			customdiff.ComputedIf("dns_records", verificationLabelChanged),
+		),
 	}
 
 	resource := custDomain.GenResource()
 	return &resource
 }
+
+func setCustomDomainDnsRecords(ctx context.Context, d *schema.ResourceData, m interface{}, response *api.Response) error {
+	tenantUrl, err := url.Parse(m.(api.ProviderConfig).Api.ApiUrl)
+	if err != nil {
+		return fmt.Errorf("Unable to determine CNAME target from api_url: %s", err)
+	}
+
+	domain := d.Get("domain").(string)
+	verificationToken := d.Get("verification_token").(string)
+	cnameTarget := tenantUrl.Hostname()
+	txtRecordName := d.Get("verification_record_label").(string) + "." + domain
+
+	records := []interface{}{
+		map[string]interface{}{
+			"type":  "TXT",
+			"name":  txtRecordName,
+			"value": verificationToken,
+		},
+		map[string]interface{}{
+			"type":  "CNAME",
+			"name":  domain,
+			"value": cnameTarget,
+		},
+	}
+
+	if err := d.Set("dns_txt_record_name", txtRecordName); err != nil {
+		return err
+	}
+	if err := d.Set("dns_txt_record_value", verificationToken); err != nil {
+		return err
+	}
+	if err := d.Set("cname_target", cnameTarget); err != nil {
+		return err
+	}
+	return d.Set("dns_records", records)
+}
+
+func verificationLabelChanged(ctx context.Context, d *schema.ResourceDiff, meta interface{}) bool {
+	return d.HasChange("verification_record_label")
+}
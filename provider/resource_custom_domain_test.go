@@ -0,0 +1,109 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestResourceCustomDomainDnsRecords(t *testing.T) {
+	client := TestClient{
+		responses: map[string]interface{}{
+			"POST https://test.api/core/v1/config/domain": map[string]interface{}{
+				"name":              "Site Worker Cert",
+				"logoUrl":           "https://s3.siteworkercert.com/logo2.jpg",
+				"domain":            "certificate.siteworkercert.com",
+				"homepage":          "https://siteworkercert.com",
+				"verificationToken": "3b2bd8cb-ac8e-4a0b-8a1c-1c9a9b3d1e4f",
+				"isVerified":        false,
+			},
+		},
+	}
+
+	createData := map[string]interface{}{
+		"name":     "Site Worker Cert",
+		"logo_url": "https://s3.siteworkercert.com/logo2.jpg",
+		"domain":   "certificate.siteworkercert.com",
+		"homepage": "https://siteworkercert.com",
+	}
+
+	resource := resourceCustomDomain(&client)
+	resourceData := runCreate(t, resource, createData, &client)
+
+	AssertEqual(t, "_mattr-verification.certificate.siteworkercert.com", resourceData.Get("dns_txt_record_name"), "TXT record name should be a label under the domain")
+	AssertEqual(t, "3b2bd8cb-ac8e-4a0b-8a1c-1c9a9b3d1e4f", resourceData.Get("dns_txt_record_value"), "TXT record value should be the verification token")
+	AssertEqual(t, "test.api", resourceData.Get("cname_target"), "CNAME target should be the tenant host")
+	AssertEqual(t, []interface{}{
+		map[string]interface{}{
+			"type":  "TXT",
+			"name":  "_mattr-verification.certificate.siteworkercert.com",
+			"value": "3b2bd8cb-ac8e-4a0b-8a1c-1c9a9b3d1e4f",
+		},
+		map[string]interface{}{
+			"type":  "CNAME",
+			"name":  "certificate.siteworkercert.com",
+			"value": "test.api",
+		},
+	}, resourceData.Get("dns_records"), "DNS records should match")
+}
+
+func TestResourceCustomDomainDnsRecordNamesAreDistinct(t *testing.T) {
+	client := TestClient{
+		responses: map[string]interface{}{
+			"POST https://test.api/core/v1/config/domain": map[string]interface{}{
+				"name":              "Site Worker Cert",
+				"logoUrl":           "https://s3.siteworkercert.com/logo2.jpg",
+				"domain":            "certificate.siteworkercert.com",
+				"homepage":          "https://siteworkercert.com",
+				"verificationToken": "3b2bd8cb-ac8e-4a0b-8a1c-1c9a9b3d1e4f",
+				"isVerified":        false,
+			},
+		},
+	}
+
+	resource := resourceCustomDomain(&client)
+	resourceData := runCreate(t, resource, map[string]interface{}{
+		"name":     "Site Worker Cert",
+		"logo_url": "https://s3.siteworkercert.com/logo2.jpg",
+		"domain":   "certificate.siteworkercert.com",
+		"homepage": "https://siteworkercert.com",
+	}, &client)
+
+	// a CNAME record can't share its name with any other record
+	names := map[string]string{}
+	for _, record := range resourceData.Get("dns_records").([]interface{}) {
+		recordMap := record.(map[string]interface{})
+		name := recordMap["name"].(string)
+		if other, ok := names[name]; ok {
+			t.Errorf("%s and %s records are both named %s", other, recordMap["type"], name)
+		}
+		names[name] = recordMap["type"].(string)
+	}
+}
+
+func TestResourceCustomDomainVerificationRecordLabel(t *testing.T) {
+	client := TestClient{
+		responses: map[string]interface{}{
+			"POST https://test.api/core/v1/config/domain": map[string]interface{}{
+				"name":              "Site Worker Cert",
+				"logoUrl":           "https://s3.siteworkercert.com/logo2.jpg",
+				"domain":            "certificate.siteworkercert.com",
+				"homepage":          "https://siteworkercert.com",
+				"verificationToken": "3b2bd8cb-ac8e-4a0b-8a1c-1c9a9b3d1e4f",
+				"isVerified":        false,
+			},
+		},
+	}
+
+	resource := resourceCustomDomain(&client)
+	resourceData := runCreate(t, resource, map[string]interface{}{
+		"name":                      "Site Worker Cert",
+		"logo_url":                  "https://s3.siteworkercert.com/logo2.jpg",
+		"domain":                    "certificate.siteworkercert.com",
+		"homepage":                  "https://siteworkercert.com",
+		"verification_record_label": "_mattr",
+	}, &client)
+
+	AssertEqual(t, "_mattr.certificate.siteworkercert.com", resourceData.Get("dns_txt_record_name"), "TXT record name should use the configured label")
+	if _, ok := client.logs[0].body.(map[string]interface{})["verificationRecordLabel"]; ok {
+		t.Error("The verification record label should not be sent to MATTR")
+	}
+}
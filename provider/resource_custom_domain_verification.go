@@ -0,0 +1,163 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"nz.antunovic/mattr-terraform-provider/api"
+	"nz.antunovic/mattr-terraform-provider/generator"
+)
+
+const customDomainPath = "/core/v1/config/domain"
+
+// customDomainPollInterval is the least time between checks of whether the
+// custom domain has been verified
+var customDomainPollInterval = 5 * time.Second
+
+// resourceCustomDomainVerification asks MATTR to verify the tenant's custom
+// domain and waits until it has been verified. It is meant to depend on the
+// DNS record holding the domain's verification token, so that both are
+// created in the same apply.
+func resourceCustomDomainVerification(client api.Client) *schema.Resource {
+	verify := func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		domain := d.Get("domain").(string)
+		providerApi := m.(api.ProviderConfig).Api
+
+		domainUrl, err := providerApi.GetUrl(customDomainPath)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		verifyUrl, err := providerApi.GetUrl(customDomainPath + "/verify")
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		var lastVerifyErr error
+		refresh := func() (interface{}, string, error) {
+			headers, err := providerApi.AuthHeaders(ctx)
+			if err != nil {
+				return nil, "", err
+			}
+
+			// MATTR rejects verification until the TXT record is visible to
+			// it, which is expected while DNS propagates
+			if _, err := client.Post(ctx, verifyUrl, headers, map[string]interface{}{}); err != nil {
+				log.Printf("Verification of %s not yet successful: %s", domain, err)
+				lastVerifyErr = err
+			}
+
+			response, err := client.Get(ctx, domainUrl, headers)
+			if err != nil {
+				return nil, "", err
+			}
+			customDomain, ok := response.Body.(map[string]interface{})
+			if !ok {
+				return nil, "", fmt.Errorf("Unexpected type for %s: %T", customDomainPath, response.Body)
+			}
+			if customDomain["domain"] != domain {
+				return nil, "", fmt.Errorf("The tenant's custom domain is %v, not %s", customDomain["domain"], domain)
+			}
+			if isVerified, _ := customDomain["isVerified"].(bool); isVerified {
+				return customDomain, "verified", nil
+			}
+			return customDomain, "unverified", nil
+		}
+
+		wait := retry.StateChangeConf{
+			Pending:    []string{"unverified"},
+			Target:     []string{"verified"},
+			Refresh:    refresh,
+			Timeout:    d.Timeout(schema.TimeoutCreate),
+			MinTimeout: customDomainPollInterval,
+		}
+
+		result, err := wait.WaitForStateContext(ctx)
+		if err != nil {
+			if lastVerifyErr != nil {
+				return diag.Errorf("Custom domain %s was not verified: %s\nLast response from MATTR: %s", domain, err, lastVerifyErr)
+			}
+			return diag.Errorf("Custom domain %s was not verified: %s", domain, err)
+		}
+
+		d.SetId(domain)
+		return diag.FromErr(setCustomDomainVerification(d, result.(map[string]interface{})))
+	}
+
+	read := func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		providerApi := m.(api.ProviderConfig).Api
+		domainUrl, err := providerApi.GetUrl(customDomainPath)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		headers, err := providerApi.AuthHeaders(ctx)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		response, err := client.Get(ctx, domainUrl, headers)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		customDomain, ok := response.Body.(map[string]interface{})
+		if !ok {
+			return diag.Errorf("Unexpected type for %s: %T", customDomainPath, response.Body)
+		}
+
+		// verify again if the domain has changed or lost its verification
+		isVerified, _ := customDomain["isVerified"].(bool)
+		if customDomain["domain"] != d.Id() || !isVerified {
+			log.Printf("Custom domain %s is no longer verified", d.Id())
+			d.SetId("")
+			return nil
+		}
+
+		return diag.FromErr(setCustomDomainVerification(d, customDomain))
+	}
+
+	// verification can't be undone, so destroying only forgets it
+	deleteVerification := func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		d.SetId("")
+		return nil
+	}
+
+	return &schema.Resource{
+		Description:   fmt.Sprintf("Verifies the custom domain at %s, waiting until verification succeeds", customDomainPath),
+		CreateContext: verify,
+		ReadContext:   read,
+		DeleteContext: deleteVerification,
+		Schema: map[string]*schema.Schema{
+			"domain": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The custom domain to verify, e.g. the `domain` of a `mattr_custom_domain`",
+			},
+			"is_verified": &schema.Schema{
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"verified_at": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+		// DNS changes can take a while to propagate
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(15 * time.Minute),
+			Read:   schema.DefaultTimeout(generator.DefaultTimeout),
+			Delete: schema.DefaultTimeout(generator.DefaultTimeout),
+		},
+	}
+}
+
+func setCustomDomainVerification(d *schema.ResourceData, customDomain map[string]interface{}) error {
+	if err := d.Set("is_verified", customDomain["isVerified"]); err != nil {
+		return err
+	}
+	return d.Set("verified_at", customDomain["verifiedAt"])
+}
@@ -0,0 +1,119 @@
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestResourceCustomDomainVerificationCreate(t *testing.T) {
+	client := TestClient{
+		responses: map[string]interface{}{
+			"POST https://test.api/core/v1/config/domain/verify": map[string]interface{}{},
+			"GET https://test.api/core/v1/config/domain": map[string]interface{}{
+				"name":              "Site Worker Cert",
+				"logoUrl":           "https://s3.siteworkercert.com/logo2.jpg",
+				"domain":            "certificate.siteworkercert.com",
+				"homepage":          "https://siteworkercert.com",
+				"verificationToken": "3b2bd8cb-ac8e-4a0b-8a1c-1c9a9b3d1e4f",
+				"isVerified":        true,
+				"verifiedAt":        "2023-11-07T03:01:05.283Z",
+			},
+		},
+	}
+
+	createData := map[string]interface{}{
+		"domain": "certificate.siteworkercert.com",
+	}
+
+	resource := resourceCustomDomainVerification(&client)
+	resourceData := runCreate(t, resource, createData, &client)
+
+	AssertEqual(t, "certificate.siteworkercert.com", resourceData.Id(), "ID should be the domain")
+	AssertEqual(t, true, resourceData.Get("is_verified"), "Domain should be verified")
+	AssertEqual(t, "2023-11-07T03:01:05.283Z", resourceData.Get("verified_at"), "Verification time should match")
+}
+
+func TestResourceCustomDomainVerificationWrongDomain(t *testing.T) {
+	client := TestClient{
+		responses: map[string]interface{}{
+			"POST https://test.api/core/v1/config/domain/verify": map[string]interface{}{},
+			"GET https://test.api/core/v1/config/domain": map[string]interface{}{
+				"domain":     "other.siteworkercert.com",
+				"isVerified": false,
+			},
+		},
+	}
+
+	resource := resourceCustomDomainVerification(&client)
+	resourceData := resource.TestResourceData()
+	resourceData.Set("domain", "certificate.siteworkercert.com")
+
+	diags := resource.CreateContext(context.Background(), resourceData, testProviderConfig())
+	if !diags.HasError() {
+		t.Fatal("Expected verification to fail for a different domain")
+	}
+}
+
+func TestResourceCustomDomainVerificationPollsUntilVerified(t *testing.T) {
+	defer func(interval time.Duration) { customDomainPollInterval = interval }(customDomainPollInterval)
+	customDomainPollInterval = time.Millisecond
+
+	client := TestClient{
+		responses: map[string]interface{}{
+			"POST https://test.api/core/v1/config/domain/verify": map[string]interface{}{},
+		},
+		sequences: map[string][]interface{}{
+			"GET https://test.api/core/v1/config/domain": {
+				map[string]interface{}{"domain": "certificate.siteworkercert.com", "isVerified": false},
+				map[string]interface{}{"domain": "certificate.siteworkercert.com", "isVerified": false},
+				map[string]interface{}{
+					"domain":     "certificate.siteworkercert.com",
+					"isVerified": true,
+					"verifiedAt": "2023-11-07T03:01:05.283Z",
+				},
+			},
+		},
+	}
+
+	resource := resourceCustomDomainVerification(&client)
+	resourceData := runCreate(t, resource, map[string]interface{}{
+		"domain": "certificate.siteworkercert.com",
+	}, &client)
+
+	AssertEqual(t, 3, client.calls["GET https://test.api/core/v1/config/domain"], "Verification should be checked until it succeeds")
+	AssertEqual(t, true, resourceData.Get("is_verified"), "Domain should be verified")
+	AssertEqual(t, "2023-11-07T03:01:05.283Z", resourceData.Get("verified_at"), "Verification time should match")
+}
+
+func TestResourceCustomDomainVerificationTimeout(t *testing.T) {
+	defer func(interval time.Duration) { customDomainPollInterval = interval }(customDomainPollInterval)
+	customDomainPollInterval = time.Millisecond
+
+	client := TestClient{
+		responses: map[string]interface{}{
+			"POST https://test.api/core/v1/config/domain/verify": map[string]interface{}{},
+			"GET https://test.api/core/v1/config/domain": map[string]interface{}{
+				"domain":     "certificate.siteworkercert.com",
+				"isVerified": false,
+			},
+		},
+	}
+
+	resource := resourceCustomDomainVerification(&client)
+	resourceData := resource.TestResourceData()
+	resourceData.Set("domain", "certificate.siteworkercert.com")
+
+	// Terraform enforces the create timeout through the context
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	diags := resource.CreateContext(ctx, resourceData, testProviderConfig())
+	if !diags.HasError() {
+		t.Fatal("Expected verification to fail when the domain is never verified")
+	}
+	AssertEqual(t, "", resourceData.Id(), "Unverified domain should not be stored")
+	if len(client.logs) < 4 {
+		t.Errorf("Verification should have been retried before timing out, but only %d requests were made", len(client.logs))
+	}
+}
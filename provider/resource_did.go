@@ -1,25 +1,98 @@
 package provider
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"log"
+	"net/http"
+	"net/url"
 	"nz.antunovic/mattr-terraform-provider/api"
 	"nz.antunovic/mattr-terraform-provider/generator"
+	"strings"
+	"time"
 )
 
-func resourceDid() *schema.Resource {
-	schema := map[string]*schema.Schema{
+func resourceDid(client api.Client) *schema.Resource {
+	didSchema := map[string]*schema.Schema{
 		"method": &schema.Schema{
-			Type:        schema.TypeString,
-			Description: "The method (or type) of did: key, web, or ion",
-			Required:    true,
-			ForceNew:    true,
+			Type:         schema.TypeString,
+			Description:  "The method (or type) of did: key, web, or ion",
+			Required:     true,
+			ForceNew:     true,
+			ValidateFunc: oneOf(didMethods),
 		},
 		"url": &schema.Schema{
 			Type:        schema.TypeString,
 			Optional:    true,
-			Description: "Domain or URL from which hostname will be extracted",
+			Description: "Domain or URL from which hostname will be extracted. Required for did:web",
+			ForceNew:    true,
+		},
+		"key_type": &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     true,
+			Description:  "Type of key to create for did:key or did:ion. Use bls12381g2 to issue credentials that support BBS+ selective disclosure",
+			ValidateFunc: oneOf(allDidKeyTypes()),
+		},
+		"wait_for_publication": &schema.Schema{
+			Type:        schema.TypeBool,
+			Optional:    true,
 			ForceNew:    true,
+			Description: "Wait until a did:ion has been published to the ION network before finishing create, bounded by the create timeout",
+			// only affects create, so changing it later shouldn't replace the DID
+			DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+				return len(d.Id()) != 0
+			},
+		},
+		"short_form_did": &schema.Schema{
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The short form of a did:ion, which resolves once it is published. The same as the ID for other methods",
+		},
+		"did_document": &schema.Schema{
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The initial DID document as JSON. For did:web, this must be published at `did_document_path` on the DID's domain",
+		},
+		"did_document_path": &schema.Schema{
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "For did:web, the path on the DID's domain at which the DID document must be published",
+		},
+		"verification_method": &schema.Schema{
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: "Verification methods from the DID document, with their public keys",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"id": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"type": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"controller": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"public_key_jwk": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "The public key as a JWK in JSON, if the DID document has one",
+					},
+					"public_key_multibase": {
+						Type:        schema.TypeString,
+						Computed:    true,
+						Description: "The public key as a base58btc multibase Multikey, if the DID document has one",
+					},
+				},
+			},
 		},
 		"keys": &schema.Schema{
 			Type:     schema.TypeList,
@@ -47,21 +120,36 @@ func resourceDid() *schema.Resource {
 				newResponse := make(map[string]interface{})
 				newResponse["keys"] = localMetadata["keys"]
 
-				did, ok := orig["did"].(string)
-				if ok && len(did) != 0 {
-					newResponse["id"] = did
-					return newResponse, nil
+				initialDidDocument, hasDidDocument := localMetadata["initialDidDocument"].(map[string]interface{})
+				if hasDidDocument {
+					didDocument, err := json.Marshal(initialDidDocument)
+					if err != nil {
+						return nil, fmt.Errorf("Unable to serialise DID document: %s", err)
+					}
+					newResponse["didDocument"] = string(didDocument)
 				}
 
-				initialDidDocument, ok := localMetadata["initialDidDocument"].(map[string]interface{})
-				if !ok {
-					return nil, fmt.Errorf("Internal error: unable to determine did")
-				}
-				did, ok = initialDidDocument["id"].(string)
-				if !ok {
-					return nil, fmt.Errorf("Internal error: unable to determine did")
+				did, ok := orig["did"].(string)
+				if !ok || len(did) == 0 {
+					if !hasDidDocument {
+						return nil, fmt.Errorf("Internal error: unable to determine did")
+					}
+					did, ok = initialDidDocument["id"].(string)
+					if !ok {
+						return nil, fmt.Errorf("Internal error: unable to determine did")
+					}
 				}
 				newResponse["id"] = did
+				newResponse["shortFormDid"] = shortFormDid(did)
+
+				if strings.HasPrefix(did, "did:web:") {
+					didDocumentPath, err := didWebDocumentPath(did)
+					if err != nil {
+						return nil, err
+					}
+					newResponse["didDocumentPath"] = didDocumentPath
+				}
+
 				return newResponse, nil
 			} else {
 				return nil, fmt.Errorf("Unexpected type for DID `localMetadata` field: %T", orig["localMetadata"])
@@ -71,14 +159,282 @@ func resourceDid() *schema.Resource {
 		}
 	}
 
+	modifyRequestBody := func(requestBody interface{}) (interface{}, error) {
+		bodyMap, ok := requestBody.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Unexpected type for DID request: %T", requestBody)
+		}
+		delete(bodyMap, "waitForPublication")
+		if keyType, ok := bodyMap["keyType"]; ok {
+			bodyMap["options"] = map[string]interface{}{
+				"keyType": keyType,
+			}
+			delete(bodyMap, "keyType")
+		}
+		return bodyMap, nil
+	}
+
+	afterOperation := func(ctx context.Context, d *schema.ResourceData, m interface{}, response *api.Response) error {
+		// verification methods have their own IDs, which the response
+		// visitor would take for the DID's
+		body, _ := response.Body.(map[string]interface{})
+		localMetadata, _ := body["localMetadata"].(map[string]interface{})
+		if initialDidDocument, ok := localMetadata["initialDidDocument"].(map[string]interface{}); ok {
+			if err := setDidVerificationMethods(d, initialDidDocument); err != nil {
+				return err
+			}
+		}
+
+		// publication is only waited for when the DID is created
+		if d.IsNewResource() && d.Get("wait_for_publication").(bool) {
+			return waitForDidPublication(ctx, d, m, client)
+		}
+		return nil
+	}
+
 	generator := generator.Generator{
-		Path:               "/core/v1/dids",
+		Path:               didPath,
 		Immutable:          true,
-		Client:             &api.HttpClient{},
-		Schema:             schema,
+		Client:             client,
+		Schema:             didSchema,
+		ModifyRequestBody:  modifyRequestBody,
 		ModifyResponseBody: modifyResponseBody,
+		CustomizeDiff:      customdiff.All(validateDidUrl, validateDidKeyType),
+		AfterOperation:     afterOperation,
+		// did:ion creation goes via the ION network, which is much slower
+		// than the other methods, especially when waiting for publication
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+		},
 	}
 
 	resource := generator.GenResource()
 	return &resource
 }
+
+// didPath is where DIDs are created, and where any DID, whether or not it was
+// created by the tenant, is resolved
+const didPath = "/core/v1/dids"
+
+func didResolvePath(did string) string {
+	return fmt.Sprintf("%s/%s", didPath, did)
+}
+
+// didPublicationPollInterval is the least time between attempts to resolve a
+// DID while waiting for it to be published
+var didPublicationPollInterval = 10 * time.Second
+
+// ionNetworks are the ION networks other than mainnet, which are named in
+// their DIDs, e.g. did:ion:test:EiD...
+var ionNetworks = map[string]bool{
+	"test": true,
+}
+
+// waitForDidPublication polls until the DID resolves. A did:ion only resolves
+// by its short form once it has been anchored on the ION network, whereas
+// other methods resolve straight away.
+func waitForDidPublication(ctx context.Context, d *schema.ResourceData, m interface{}, client api.Client) error {
+	did := shortFormDid(d.Id())
+	providerApi := m.(api.ProviderConfig).Api
+	resolveUrl, err := providerApi.GetUrl(didResolvePath(did))
+	if err != nil {
+		return err
+	}
+
+	refresh := func() (interface{}, string, error) {
+		headers, err := providerApi.AuthHeaders(ctx)
+		if err != nil {
+			return nil, "", err
+		}
+		response, err := client.Get(ctx, resolveUrl, headers)
+		if apiError, ok := err.(api.ApiError); ok && apiError.StatusCode == http.StatusNotFound {
+			log.Printf("%s doesn't resolve yet", did)
+			return apiError, "PENDING", nil
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		resolution, ok := response.Body.(map[string]interface{})
+		if !ok {
+			return nil, "", fmt.Errorf("Unexpected type for DID resolution: %T", response.Body)
+		}
+		if _, ok := resolution["didDocument"].(map[string]interface{}); !ok {
+			log.Printf("%s resolved without a DID document", did)
+			return resolution, "PENDING", nil
+		}
+		log.Printf("%s has been published", did)
+		return resolution, "COMPLETED", nil
+	}
+
+	wait := retry.StateChangeConf{
+		Pending:    []string{"PENDING"},
+		Target:     []string{"COMPLETED"},
+		Refresh:    refresh,
+		Timeout:    d.Timeout(schema.TimeoutCreate),
+		MinTimeout: didPublicationPollInterval,
+	}
+
+	if _, err := wait.WaitForStateContext(ctx); err != nil {
+		return fmt.Errorf("%s was created but not published: %s", d.Id(), err)
+	}
+	return nil
+}
+
+// shortFormDid strips the initial state from a long-form did:ion, which is
+// only needed until the DID is published. The method-specific ID is the
+// suffix, optionally preceded by the network and followed by the state.
+func shortFormDid(did string) string {
+	parts := strings.Split(did, ":")
+	if len(parts) < 3 || parts[0] != "did" || parts[1] != "ion" {
+		return did
+	}
+
+	suffix := 2
+	if ionNetworks[parts[2]] {
+		suffix = 3
+	}
+	if len(parts) <= suffix+1 {
+		return did
+	}
+	return strings.Join(parts[:suffix+1], ":")
+}
+
+// setDidVerificationMethods sets the verification methods of a DID document,
+// including key agreement keys, with the public keys as JWK or Multikey
+func setDidVerificationMethods(d *schema.ResourceData, didDocument map[string]interface{}) error {
+	verificationMethods, err := didVerificationMethods(didDocument)
+	if err != nil {
+		return err
+	}
+	return d.Set("verification_method", verificationMethods)
+}
+
+func didVerificationMethods(didDocument map[string]interface{}) ([]interface{}, error) {
+	verificationMethods := make([]interface{}, 0)
+
+	candidates, _ := didDocument["verificationMethod"].([]interface{})
+	if keyAgreement, ok := didDocument["keyAgreement"].([]interface{}); ok {
+		candidates = append(candidates, keyAgreement...)
+	}
+
+	for i, candidate := range candidates {
+		// methods can also be referenced by ID, which we will have already seen
+		method, ok := candidate.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		methodType, _ := method["type"].(string)
+		verificationMethod := map[string]interface{}{
+			"id":         method["id"],
+			"type":       methodType,
+			"controller": method["controller"],
+		}
+
+		if jwk, ok := method["publicKeyJwk"]; ok {
+			jwkJson, err := json.Marshal(jwk)
+			if err != nil {
+				return nil, fmt.Errorf("Unable to serialise public key of verification method %d: %s", i, err)
+			}
+			verificationMethod["public_key_jwk"] = string(jwkJson)
+		}
+
+		if multibase, ok := method["publicKeyMultibase"].(string); ok {
+			verificationMethod["public_key_multibase"] = multibase
+		} else if base58, ok := method["publicKeyBase58"].(string); ok {
+			multikey, err := base58Multikey(methodType, base58)
+			if err != nil {
+				// the key is still in the DID document
+				log.Printf("Unable to convert public key of verification method %d to a Multikey: %s", i, err)
+			} else {
+				verificationMethod["public_key_multibase"] = multikey
+			}
+		}
+
+		verificationMethods = append(verificationMethods, verificationMethod)
+	}
+
+	return verificationMethods, nil
+}
+
+// didWebDocumentPath is where a did:web's document is resolved from, following
+// https://w3c-ccg.github.io/did-method-web/#read-resolve. A DID with only a
+// domain uses /.well-known/did.json, otherwise the remaining colon-separated
+// parts form the path.
+func didWebDocumentPath(did string) (string, error) {
+	parts := strings.Split(strings.TrimPrefix(did, "did:web:"), ":")
+	if len(parts[0]) == 0 {
+		return "", fmt.Errorf("Unable to determine domain of %s", did)
+	}
+
+	if len(parts) == 1 {
+		return "/.well-known/did.json", nil
+	}
+
+	segments := make([]string, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		segment, err := url.PathUnescape(part)
+		if err != nil {
+			return "", fmt.Errorf("Invalid path in %s: %s", did, err)
+		}
+		segments = append(segments, segment)
+	}
+
+	return "/" + strings.Join(segments, "/") + "/did.json", nil
+}
+
+func allDidKeyTypes() []string {
+	keyTypes := make([]string, 0)
+	for _, method := range didMethods {
+		for _, keyType := range didKeyTypes[method] {
+			if !containsString(keyTypes, keyType) {
+				keyTypes = append(keyTypes, keyType)
+			}
+		}
+	}
+	return keyTypes
+}
+
+// validateDidKeyType checks that the DID method supports the requested key
+// type
+func validateDidKeyType(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("method") || !d.NewValueKnown("key_type") {
+		return nil
+	}
+
+	method := d.Get("method").(string)
+	keyType := d.Get("key_type").(string)
+	if len(keyType) == 0 {
+		return nil
+	}
+
+	allowed := didKeyTypes[method]
+	if len(allowed) == 0 {
+		return fmt.Errorf("'key_type' can't be set when 'method' is %q", method)
+	}
+	if !containsString(allowed, keyType) {
+		return fmt.Errorf("'key_type' must be one of %q when 'method' is %q, not %q", allowed, method, keyType)
+	}
+
+	return nil
+}
+
+// validateDidUrl requires `url` when a did:web is requested, and rejects it
+// for the other methods, which have nowhere to put it.
+func validateDidUrl(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("method") || !d.NewValueKnown("url") {
+		return nil
+	}
+
+	method := d.Get("method").(string)
+	url := d.Get("url").(string)
+
+	if method == "web" && len(url) == 0 {
+		return fmt.Errorf("'url' is required when 'method' is \"web\"")
+	}
+	if method != "web" && len(url) != 0 {
+		return fmt.Errorf("'url' can only be set when 'method' is \"web\", not %q", method)
+	}
+
+	return nil
+}
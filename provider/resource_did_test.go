@@ -0,0 +1,230 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"nz.antunovic/mattr-terraform-provider/api"
+)
+
+func loadDidFixture(t *testing.T) map[string]interface{} {
+	fixture, err := os.ReadFile("../test/simple-did/core/v1/dids/did:web:organization.com.json")
+	if err != nil {
+		t.Fatalf("Error reading test data: %s", err)
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(fixture, &data); err != nil {
+		t.Fatalf("Error in test data: %s", err)
+	}
+	return data
+}
+
+func TestResourceDidWebDocument(t *testing.T) {
+	client := TestClient{
+		responses: map[string]interface{}{
+			"POST https://test.api/core/v1/dids": loadDidFixture(t),
+		},
+	}
+
+	createData := map[string]interface{}{
+		"method": "web",
+		"url":    "organization.com",
+	}
+
+	resource := resourceDid(&client)
+	resourceData := runCreate(t, resource, createData, &client)
+
+	AssertEqual(t, "did:web:organization.com", resourceData.Id(), "ID should be the DID")
+	AssertEqual(t, "/.well-known/did.json", resourceData.Get("did_document_path"), "did:web with no path should use .well-known")
+
+	var didDocument map[string]interface{}
+	if err := json.Unmarshal([]byte(resourceData.Get("did_document").(string)), &didDocument); err != nil {
+		t.Fatalf("DID document should be JSON: %s", err)
+	}
+	AssertEqual(t, "did:web:organization.com", didDocument["id"], "DID document should be for the DID")
+	AssertEqual(t, 4, len(resourceData.Get("keys").([]interface{})), "Keys should still be set")
+
+	verificationMethods := resourceData.Get("verification_method").([]interface{})
+	AssertEqual(t, 4, len(verificationMethods), "Verification methods should include key agreement keys")
+
+	p256 := verificationMethods[0].(map[string]interface{})
+	AssertEqual(t, "did:web:organization.com#z12KiP7r", p256["id"], "Verification method ID should match")
+	AssertEqual(t, "JsonWebKey2020", p256["type"], "Verification method type should match")
+	AssertEqual(t, `{"crv":"P-256","kty":"EC","x":"PZWoBmV7vjJ55Aq5hFAPIH6uDA-V3G0ueVe22ahgL7w","y":"7kzcj257Zvfpzyj2gFWrnCIbXZxQ6WyDOo2MdA6mpMI"}`, p256["public_key_jwk"], "JWK should match")
+
+	ed25519 := verificationMethods[1].(map[string]interface{})
+	AssertEqual(t, "z6MkgNsmdbyrQAvnwaRZvy7eewfiPT7JjLuYWkFDWrM1zkoS", ed25519["public_key_multibase"], "Base58 key should be a Multikey")
+
+	x25519 := verificationMethods[3].(map[string]interface{})
+	AssertEqual(t, "did:web:organization.com#CU6dJt9p8t", x25519["id"], "Key agreement method ID should match")
+	AssertEqual(t, "z6LSo9GnqBxgEMeyA69jo8mYpQgqd3dNoeCkNevhNqZMevJs", x25519["public_key_multibase"], "X25519 key should have its own multicodec prefix")
+}
+
+func TestResourceDidKeyType(t *testing.T) {
+	client := TestClient{
+		responses: map[string]interface{}{
+			"POST https://test.api/core/v1/dids": loadDidFixture(t),
+		},
+	}
+
+	createData := map[string]interface{}{
+		"method":   "key",
+		"key_type": "bls12381g2",
+	}
+
+	resource := resourceDid(&client)
+	runCreate(t, resource, createData, &client)
+
+	AssertEqual(t, map[string]interface{}{
+		"method": "key",
+		"options": map[string]interface{}{
+			"keyType": "bls12381g2",
+		},
+	}, client.logs[0].body, "Key type should be sent as an option")
+}
+
+func TestResourceDidKeyTypeForMethod(t *testing.T) {
+	resource := resourceDid(&TestClient{})
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"method":   "ion",
+		"key_type": "p256",
+	})
+	if _, err := resource.Diff(context.Background(), nil, config, nil); err == nil {
+		t.Fatal("Expected an error for a key type did:ion doesn't support")
+	}
+
+	config = terraform.NewResourceConfigRaw(map[string]interface{}{
+		"method":   "web",
+		"url":      "organization.com",
+		"key_type": "ed25519",
+	})
+	if _, err := resource.Diff(context.Background(), nil, config, nil); err == nil {
+		t.Fatal("Expected an error for a key type with did:web")
+	}
+}
+
+func TestDidWebDocumentPath(t *testing.T) {
+	cases := map[string]string{
+		"did:web:organization.com":                   "/.well-known/did.json",
+		"did:web:organization.com%3A3000":            "/.well-known/did.json",
+		"did:web:organization.com:user:alice":        "/user/alice/did.json",
+		"did:web:organization.com:issuers:some%20id": "/issuers/some id/did.json",
+	}
+
+	for did, expected := range cases {
+		path, err := didWebDocumentPath(did)
+		if err != nil {
+			t.Fatalf("Unexpected error for %s: %s", did, err)
+		}
+		AssertEqual(t, expected, path, "Path for "+did+" should match")
+	}
+}
+
+func TestResourceDidWaitForPublication(t *testing.T) {
+	defer func(interval time.Duration) { didPublicationPollInterval = interval }(didPublicationPollInterval)
+	didPublicationPollInterval = time.Millisecond
+
+	longForm := "did:ion:test:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A:eyJkZWx0YSI6eyJwYXRjaGVzIjpbXX19"
+	shortForm := "did:ion:test:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A"
+
+	did := map[string]interface{}{
+		"did": longForm,
+		"localMetadata": map[string]interface{}{
+			"keys": []interface{}{},
+		},
+	}
+	client := TestClient{
+		responses: map[string]interface{}{
+			"POST https://test.api/core/v1/dids": did,
+		},
+		sequences: map[string][]interface{}{
+			// the short form doesn't resolve until the DID is anchored
+			"GET https://test.api/core/v1/dids/" + shortForm: {
+				api.ApiError{StatusCode: http.StatusNotFound, Message: "DID not found"},
+				map[string]interface{}{
+					"didDocument": map[string]interface{}{"id": shortForm},
+				},
+			},
+		},
+	}
+
+	createData := map[string]interface{}{
+		"method":               "ion",
+		"wait_for_publication": true,
+	}
+
+	resource := resourceDid(&client)
+	resourceData := runCreate(t, resource, createData, &client)
+
+	AssertEqual(t, longForm, resourceData.Id(), "ID should be the long-form DID")
+	AssertEqual(t, shortForm, resourceData.Get("short_form_did"), "Short-form DID should keep the network")
+	AssertEqual(t, 2, client.calls["GET https://test.api/core/v1/dids/"+shortForm], "Short-form DID should be resolved until it is published")
+}
+
+func TestShortFormDid(t *testing.T) {
+	cases := map[string]string{
+		"did:ion:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A:eyJkZWx0YSI6e30":      "did:ion:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A",
+		"did:ion:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A":                      "did:ion:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A",
+		"did:ion:test:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A:eyJkZWx0YSI6e30": "did:ion:test:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A",
+		"did:ion:test:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A":                 "did:ion:test:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A",
+		"did:web:organization.com:user:alice":                                         "did:web:organization.com:user:alice",
+	}
+
+	for did, expected := range cases {
+		AssertEqual(t, expected, shortFormDid(did), "Short form of "+did+" should match")
+	}
+}
+
+func TestResourceDidReadDoesNotWaitForPublication(t *testing.T) {
+	longForm := "did:ion:EiDahaOGH-liLLdDtTxEAdc8i-cfCz-WUcQdRJheMVNn3A:eyJkZWx0YSI6eyJwYXRjaGVzIjpbXX19"
+	client := TestClient{
+		responses: map[string]interface{}{
+			"GET https://test.api/core/v1/dids/" + longForm: map[string]interface{}{
+				"did": longForm,
+				"localMetadata": map[string]interface{}{
+					"keys": []interface{}{},
+				},
+			},
+		},
+	}
+
+	resource := resourceDid(&client)
+	resourceData := resource.TestResourceData()
+	resourceData.SetId(longForm)
+	resourceData.Set("method", "ion")
+	resourceData.Set("wait_for_publication", true)
+
+	diags := resource.ReadContext(context.Background(), resourceData, testProviderConfig())
+	if diags.HasError() {
+		t.Fatalf("Read failed: %v", diags)
+	}
+	AssertEqual(t, 1, len(client.logs), "Only the DID should be read")
+}
+
+func TestResourceDidWebRequiresUrl(t *testing.T) {
+	resource := resourceDid(&TestClient{})
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"method": "web",
+	})
+
+	_, err := resource.Diff(context.Background(), nil, config, nil)
+	if err == nil {
+		t.Fatal("Expected an error when did:web has no url")
+	}
+
+	config = terraform.NewResourceConfigRaw(map[string]interface{}{
+		"method": "web",
+		"url":    "organization.com",
+	})
+	_, err = resource.Diff(context.Background(), nil, config, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error for did:web with url: %s", err)
+	}
+}
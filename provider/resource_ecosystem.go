@@ -0,0 +1,141 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"nz.antunovic/mattr-terraform-provider/api"
+	"nz.antunovic/mattr-terraform-provider/generator"
+)
+
+const ecosystemPath = "/v1/ecosystems"
+
+// resourceEcosystem manages an ecosystem, the trust registry its participants
+// and credential types belong to
+func resourceEcosystem(client api.Client) *schema.Resource {
+	generator := generator.Generator{
+		Path:   ecosystemPath,
+		Client: client,
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"description": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+		},
+	}
+
+	resource := generator.GenResource()
+	return &resource
+}
+
+// resourceEcosystemParticipant manages an organisation in an ecosystem, and
+// the roles it is trusted in
+func resourceEcosystemParticipant(client api.Client) *schema.Resource {
+	generator := generator.Generator{
+		GetPath: ecosystemChildPath("participants"),
+		Client:  client,
+		Schema: map[string]*schema.Schema{
+			"ecosystem_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"roles": &schema.Schema{
+				Type:        schema.TypeSet,
+				Required:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: oneOf(ecosystemParticipantRoles)},
+				Description: "Roles the participant is trusted in: issuer, verifier or both",
+			},
+			"dids": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "DIDs the participant issues and verifies web credentials with",
+			},
+			"certificate_pems": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "IACA certificates the participant issues mDocs with, in PEM format",
+			},
+		},
+		ModifyRequestBody: withoutEcosystemId,
+	}
+
+	resource := generator.GenResource()
+	resource.Description = fmt.Sprintf("Represents a participant at %s/{ecosystem_id}/participants", ecosystemPath)
+	return &resource
+}
+
+// resourceEcosystemCredentialType manages a type of credential in an
+// ecosystem, and which participants may issue and verify it
+func resourceEcosystemCredentialType(client api.Client) *schema.Resource {
+	generator := generator.Generator{
+		GetPath: ecosystemChildPath("credential-types"),
+		Client:  client,
+		Schema: map[string]*schema.Schema{
+			"ecosystem_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"type": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The web credential type or mDoc doctype, e.g. org.iso.18013.5.1.mDL",
+			},
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"issuer_ids": &schema.Schema{
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "IDs of the participants allowed to issue this credential type",
+			},
+			"verifier_ids": &schema.Schema{
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "IDs of the participants allowed to verify this credential type",
+			},
+		},
+		ModifyRequestBody: withoutEcosystemId,
+	}
+
+	resource := generator.GenResource()
+	resource.Description = fmt.Sprintf("Represents a credential type at %s/{ecosystem_id}/credential-types", ecosystemPath)
+	return &resource
+}
+
+// ecosystemChildPath returns a GetPath for the collection of an ecosystem
+// that the resource belongs to
+func ecosystemChildPath(collection string) func(*schema.ResourceData) (string, error) {
+	return func(d *schema.ResourceData) (string, error) {
+		if ecosystemId, ok := d.Get("ecosystem_id").(string); ok && len(ecosystemId) != 0 {
+			return fmt.Sprintf("%s/%s/%s", ecosystemPath, ecosystemId, collection), nil
+		}
+
+		return "", fmt.Errorf("'ecosystem_id' field is required for ecosystem %s and must be a string", collection)
+	}
+}
+
+func withoutEcosystemId(body interface{}) (interface{}, error) {
+	bodyMap, ok := body.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Unexpected type for ecosystem request: %T", body)
+	}
+	// the ecosystem is in the path
+	delete(bodyMap, "ecosystemId")
+	return bodyMap, nil
+}
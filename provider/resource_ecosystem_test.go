@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestResourceEcosystemParticipant(t *testing.T) {
+	client := TestClient{
+		responses: map[string]interface{}{
+			"POST https://test.api/v1/ecosystems/0f4a2c9e-5b1d-4e7a-9c3f-8d2b6a1e4f70/participants": map[string]interface{}{
+				"id":    "b3e1f7a2-6c4d-4f8e-a9b0-1d2c3e4f5a6b",
+				"name":  "Example transport agency",
+				"roles": []interface{}{"issuer"},
+				"dids":  []interface{}{"did:web:issuer.example.com"},
+			},
+		},
+	}
+
+	resource := resourceEcosystemParticipant(&client)
+	resourceData := runCreate(t, resource, map[string]interface{}{
+		"ecosystem_id": "0f4a2c9e-5b1d-4e7a-9c3f-8d2b6a1e4f70",
+		"name":         "Example transport agency",
+		"roles":        []interface{}{"issuer"},
+		"dids":         []interface{}{"did:web:issuer.example.com"},
+	}, &client)
+
+	AssertEqual(t, "b3e1f7a2-6c4d-4f8e-a9b0-1d2c3e4f5a6b", resourceData.Id(), "ID should match")
+	AssertEqual(t, "0f4a2c9e-5b1d-4e7a-9c3f-8d2b6a1e4f70", resourceData.Get("ecosystem_id"), "Ecosystem ID should be kept")
+	AssertEqual(t, nil, client.logs[0].body.(map[string]interface{})["ecosystemId"], "Ecosystem ID should only be in the path")
+}
+
+func TestResourceEcosystemCredentialTypeUpdate(t *testing.T) {
+	client := TestClient{
+		responses: map[string]interface{}{
+			"POST https://test.api/v1/ecosystems/0f4a2c9e-5b1d-4e7a-9c3f-8d2b6a1e4f70/credential-types": map[string]interface{}{
+				"id":        "5c6d7e8f-9a0b-4c1d-8e2f-3a4b5c6d7e8f",
+				"type":      "org.iso.18013.5.1.mDL",
+				"issuerIds": []interface{}{"b3e1f7a2-6c4d-4f8e-a9b0-1d2c3e4f5a6b"},
+			},
+			"PUT https://test.api/v1/ecosystems/0f4a2c9e-5b1d-4e7a-9c3f-8d2b6a1e4f70/credential-types/5c6d7e8f-9a0b-4c1d-8e2f-3a4b5c6d7e8f": map[string]interface{}{
+				"id":          "5c6d7e8f-9a0b-4c1d-8e2f-3a4b5c6d7e8f",
+				"type":        "org.iso.18013.5.1.mDL",
+				"issuerIds":   []interface{}{"b3e1f7a2-6c4d-4f8e-a9b0-1d2c3e4f5a6b"},
+				"verifierIds": []interface{}{"e9d8c7b6-a5f4-4e3d-9c2b-1a0f9e8d7c6b"},
+			},
+		},
+	}
+
+	resource := resourceEcosystemCredentialType(&client)
+	state := runUpdate(t, resource, map[string]interface{}{
+		"ecosystem_id": "0f4a2c9e-5b1d-4e7a-9c3f-8d2b6a1e4f70",
+		"type":         "org.iso.18013.5.1.mDL",
+		"issuer_ids":   []interface{}{"b3e1f7a2-6c4d-4f8e-a9b0-1d2c3e4f5a6b"},
+	}, map[string]interface{}{
+		"ecosystem_id": "0f4a2c9e-5b1d-4e7a-9c3f-8d2b6a1e4f70",
+		"type":         "org.iso.18013.5.1.mDL",
+		"issuer_ids":   []interface{}{"b3e1f7a2-6c4d-4f8e-a9b0-1d2c3e4f5a6b"},
+		"verifier_ids": []interface{}{"e9d8c7b6-a5f4-4e3d-9c2b-1a0f9e8d7c6b"},
+	}, &client)
+
+	AssertEqual(t, "PUT", client.logs[1].method, "Credential type should be updated in place")
+	AssertEqual(t, "1", state.Attributes["verifier_ids.#"], "Verifier should be added")
+}
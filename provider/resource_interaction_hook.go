@@ -0,0 +1,131 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"nz.antunovic/mattr-terraform-provider/api"
+	"nz.antunovic/mattr-terraform-provider/generator"
+)
+
+// resourceInteractionHook manages the interaction hook of the tenant's OIDC
+// issuance configuration
+func resourceInteractionHook(client api.Client) *schema.Resource {
+	write := func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		interactionHook := map[string]interface{}{
+			"url":      d.Get("url"),
+			"claims":   d.Get("claims"),
+			"disabled": !d.Get("enabled").(bool),
+		}
+		if sessionTimeout, ok := d.GetOk("session_timeout_in_sec"); ok {
+			interactionHook["sessionTimeoutInSec"] = sessionTimeout
+		}
+		// MATTR generates a secret if none is sent, and the one in state
+		// is sent back on update so that it doesn't change
+		if secret, ok := d.GetOk("secret"); ok {
+			interactionHook["secret"] = secret
+		}
+
+		configuration, err := putOpenidConfigurationProperty(ctx, client, m, "interactionHook", interactionHook)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		d.SetId(openidConfigurationPath)
+		return diag.FromErr(setInteractionHook(d, configuration))
+	}
+
+	read := func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		configuration, err := getOpenidConfiguration(ctx, client, m)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if configuration["interactionHook"] == nil {
+			d.SetId("")
+			return nil
+		}
+		return diag.FromErr(setInteractionHook(d, configuration))
+	}
+
+	deleteInteractionHook := func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		if _, err := putOpenidConfigurationProperty(ctx, client, m, "interactionHook", nil); err != nil {
+			return diag.FromErr(err)
+		}
+		d.SetId("")
+		return nil
+	}
+
+	return &schema.Resource{
+		Description:   fmt.Sprintf("Configures the interaction hook in %s, which sends users to your app during OIDC issuance", openidConfigurationPath),
+		CreateContext: write,
+		ReadContext:   read,
+		UpdateContext: write,
+		DeleteContext: deleteInteractionHook,
+		Schema: map[string]*schema.Schema{
+			"url": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.IsURLWithHTTPS,
+				Description:  "URL of your app that users are redirected to",
+			},
+			"claims": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Claims from the authentication provider to pass to the hook",
+			},
+			"session_timeout_in_sec": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+			"enabled": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"secret": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Secret your app uses to verify and sign the hook's JWTs. Generated by MATTR if not set. MATTR doesn't return it when read, so changes made outside Terraform aren't detected",
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(generator.DefaultTimeout),
+			Read:   schema.DefaultTimeout(generator.DefaultTimeout),
+			Update: schema.DefaultTimeout(generator.DefaultTimeout),
+			Delete: schema.DefaultTimeout(generator.DefaultTimeout),
+		},
+	}
+}
+
+func setInteractionHook(d *schema.ResourceData, configuration map[string]interface{}) error {
+	interactionHook, ok := configuration["interactionHook"].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("Unexpected type for %s 'interactionHook' field: %T", openidConfigurationPath, configuration["interactionHook"])
+	}
+
+	if err := d.Set("url", interactionHook["url"]); err != nil {
+		return err
+	}
+	if err := d.Set("claims", interactionHook["claims"]); err != nil {
+		return err
+	}
+	if err := d.Set("session_timeout_in_sec", interactionHook["sessionTimeoutInSec"]); err != nil {
+		return err
+	}
+	disabled, _ := interactionHook["disabled"].(bool)
+	if err := d.Set("enabled", !disabled); err != nil {
+		return err
+	}
+	// the secret is write-only, and only returned when it's generated
+	if secret, ok := interactionHook["secret"].(string); ok && len(secret) != 0 {
+		return d.Set("secret", secret)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package provider
+
+import (
+	"context"
+	"testing"
+)
+
+func TestResourceInteractionHookCreate(t *testing.T) {
+	client := TestClient{
+		responses: map[string]interface{}{
+			"GET https://test.api/core/v1/openid/configuration": map[string]interface{}{
+				"credentials": []interface{}{"983c0a86-204f-4431-9371-f5a22e506599"},
+			},
+			"PUT https://test.api/core/v1/openid/configuration": map[string]interface{}{
+				"credentials": []interface{}{"983c0a86-204f-4431-9371-f5a22e506599"},
+				"interactionHook": map[string]interface{}{
+					"url":                 "https://app.example.com/hook",
+					"claims":              []interface{}{"email"},
+					"sessionTimeoutInSec": 600.0,
+					"disabled":            false,
+					"secret":              "bWF0dHJzZWNyZXQ=",
+				},
+			},
+		},
+	}
+
+	resource := resourceInteractionHook(&client)
+	resourceData := runCreate(t, resource, map[string]interface{}{
+		"url":    "https://app.example.com/hook",
+		"claims": []interface{}{"email"},
+	}, &client)
+
+	body := client.logs[1].body.(map[string]interface{})
+	AssertEqual(t, []interface{}{"983c0a86-204f-4431-9371-f5a22e506599"}, body["credentials"], "The rest of the configuration should be sent back unchanged")
+	AssertEqual(t, nil, body["interactionHook"].(map[string]interface{})["secret"], "No secret should be sent so MATTR generates one")
+
+	AssertEqual(t, "bWF0dHJzZWNyZXQ=", resourceData.Get("secret"), "Generated secret should be exposed")
+	AssertEqual(t, true, resourceData.Get("enabled"), "Hook should be enabled")
+	AssertEqual(t, 600, resourceData.Get("session_timeout_in_sec"), "Session timeout should match")
+}
+
+func TestResourceInteractionHookKeepsSecretOnRead(t *testing.T) {
+	client := TestClient{
+		responses: map[string]interface{}{
+			"GET https://test.api/core/v1/openid/configuration": map[string]interface{}{
+				"interactionHook": map[string]interface{}{
+					"url":      "https://app.example.com/hook",
+					"disabled": true,
+				},
+			},
+		},
+	}
+
+	resource := resourceInteractionHook(&client)
+	resourceData := resource.TestResourceData()
+	resourceData.SetId(openidConfigurationPath)
+	resourceData.Set("secret", "bWF0dHJzZWNyZXQ=")
+
+	diags := resource.ReadContext(context.Background(), resourceData, testProviderConfig())
+	if diags.HasError() {
+		t.Fatalf("Read failed: %v", diags)
+	}
+
+	AssertEqual(t, "bWF0dHJzZWNyZXQ=", resourceData.Get("secret"), "Secret should be kept when MATTR doesn't return it")
+	AssertEqual(t, false, resourceData.Get("enabled"), "Hook should be disabled")
+}
+
+func TestResourceInteractionHookDelete(t *testing.T) {
+	client := TestClient{
+		responses: map[string]interface{}{
+			"GET https://test.api/core/v1/openid/configuration": map[string]interface{}{
+				"credentials":     []interface{}{"983c0a86-204f-4431-9371-f5a22e506599"},
+				"interactionHook": map[string]interface{}{"url": "https://app.example.com/hook"},
+			},
+			"PUT https://test.api/core/v1/openid/configuration": map[string]interface{}{
+				"credentials": []interface{}{"983c0a86-204f-4431-9371-f5a22e506599"},
+			},
+		},
+	}
+
+	resource := resourceInteractionHook(&client)
+	resourceData := resource.TestResourceData()
+	resourceData.SetId(openidConfigurationPath)
+
+	diags := resource.DeleteContext(context.Background(), resourceData, testProviderConfig())
+	if diags.HasError() {
+		t.Fatalf("Delete failed: %v", diags)
+	}
+
+	AssertEqual(t, map[string]interface{}{
+		"credentials": []interface{}{"983c0a86-204f-4431-9371-f5a22e506599"},
+	}, client.logs[1].body, "Only the interaction hook should be removed")
+}
+
+func TestResourceInteractionHookKeepsCurrentSecret(t *testing.T) {
+	client := TestClient{
+		responses: map[string]interface{}{
+			"GET https://test.api/core/v1/openid/configuration": map[string]interface{}{
+				"interactionHook": map[string]interface{}{
+					"url":    "https://app.example.com/hook",
+					"secret": "bWF0dHJzZWNyZXQ=",
+				},
+			},
+			"PUT https://test.api/core/v1/openid/configuration": map[string]interface{}{
+				"interactionHook": map[string]interface{}{
+					"url": "https://app.example.com/new-hook",
+				},
+			},
+		},
+	}
+
+	// e.g. after import, when there's no secret in state
+	resource := resourceInteractionHook(&client)
+	runCreate(t, resource, map[string]interface{}{
+		"url": "https://app.example.com/new-hook",
+	}, &client)
+
+	interactionHook := client.logs[1].body.(map[string]interface{})["interactionHook"].(map[string]interface{})
+	AssertEqual(t, "bWF0dHJzZWNyZXQ=", interactionHook["secret"], "The current secret should be sent so MATTR doesn't generate a new one")
+}
@@ -1,13 +1,16 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 	"nz.antunovic/mattr-terraform-provider/api"
 	"nz.antunovic/mattr-terraform-provider/generator"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
-func resourceIssuer() *schema.Resource {
+const issuerPath = "/ext/oidc/v1/issuers"
+
+func resourceIssuer(client api.Client) *schema.Resource {
 	issuerSchema := map[string]*schema.Schema{
 		"issuer_did": &schema.Schema{
 			Type:     schema.TypeString,
@@ -44,8 +47,9 @@ func resourceIssuer() *schema.Resource {
 			Elem:     &schema.Schema{Type: schema.TypeString},
 		},
 		"proof_type": &schema.Schema{
-			Type:     schema.TypeString,
-			Optional: true,
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: oneOf(proofTypes),
 		},
 		"background_color": &schema.Schema{
 			Type:     schema.TypeString,
@@ -73,8 +77,9 @@ func resourceIssuer() *schema.Resource {
 			Required: true,
 		},
 		"token_endpoint_auth_method": &schema.Schema{
-			Type:     schema.TypeString,
-			Optional: true,
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: oneOf(tokenEndpointAuthMethods),
 		},
 		"callback_url": &schema.Schema{
 			Type:     schema.TypeString,
@@ -117,61 +122,27 @@ func resourceIssuer() *schema.Resource {
 	}
 
 	issuerGenerator := generator.Generator{
-		Path:               "/ext/oidc/v1/issuers",
-		Schema:             issuerSchema,
-		Client:             &api.HttpClient{},
-		ModifyRequestBody:  issuerConvertReq,
-		ModifyResponseBody: issuerConvertRes,
+		Path:                  issuerPath,
+		Schema:                issuerSchema,
+		Client:                client,
+		ModifyRequestBody:     issuerConvertReq,
+		ModifyResponseBody:    issuerConvertRes,
+		AfterOperation:        setIssuerOpenidConfigurationUrl,
+		OptimisticConcurrency: true,
 	}
 
 	issuerResource := issuerGenerator.GenResource()
-
-	// we need to integrate data from generator and resource data
-	// to compute openid-configuration url
-	// TODO: this is complicated because the generator "modify" functions aren't 
-	// powerful enough.
-
-	createOrig := issuerResource.Create
-	readOrig := issuerResource.Read
-	updateOrig := issuerResource.Update
-
-	issuerResource.Create = func (d *schema.ResourceData, m interface{}) error {
-		err := createOrig(d, m)
-		if err != nil {
-			return err
-		}
-		return setOpenIdConfigurationUrl(d, m)
-	}
-
-	issuerResource.Read = func (d *schema.ResourceData, m interface{}) error {
-		err := readOrig(d, m)
-		if err != nil {
-			return err
-		}
-		return setOpenIdConfigurationUrl(d, m)
-	}
-
-	issuerResource.Update = func (d *schema.ResourceData, m interface{}) error {
-		err := updateOrig(d, m)
-		if err != nil {
-			return err
-		}
-		return setOpenIdConfigurationUrl(d, m)
-	}
-
 	return &issuerResource
 }
 
-func setOpenIdConfigurationUrl(d *schema.ResourceData, m interface{}) error {
+func setIssuerOpenidConfigurationUrl(ctx context.Context, d *schema.ResourceData, m interface{}, response *api.Response) error {
 	// e.g. GET https://YOUR_TENANT_URL/ext/oidc/v1/issuers/983c0a86-204f-4431-9371-f5a22e506599/.well-known/openid-configuration
-	api := m.(api.ProviderConfig).Api
-	id := d.Id()
-	path := fmt.Sprintf("%s/.well-known/openid-configuration", id)
-	openIdConfigurationUrl, err := api.GetUrl(path)
+	providerApi := m.(api.ProviderConfig).Api
+	openidConfigurationUrl, err := providerApi.GetUrl(fmt.Sprintf("%s/%s/.well-known/openid-configuration", issuerPath, d.Id()))
 	if err != nil {
-		return d.Set("openid_configuration_url", openIdConfigurationUrl)
+		return err
 	}
-	return err
+	return d.Set("openid_configuration_url", openidConfigurationUrl)
 }
 
 func issuerConvertReq(body interface{}) (interface{}, error) {
@@ -264,3 +235,12 @@ func castToStringSlice(val interface{}) []string {
 	}
 	return stringSlice
 }
+
+func containsString(slice []string, value string) bool {
+	for _, s := range slice {
+		if s == value {
+			return true
+		}
+	}
+	return false
+}
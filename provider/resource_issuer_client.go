@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 
 	"nz.antunovic/mattr-terraform-provider/api"
@@ -27,24 +28,33 @@ func resourceIssuerClient() *schema.Resource {
 		"response_types": &schema.Schema{
 			Type:     schema.TypeList,
 			Optional: true,
-			Elem:     &schema.Schema{Type: schema.TypeString},
+			Elem: &schema.Schema{
+				Type:         schema.TypeString,
+				ValidateFunc: oneOf(responseTypes),
+			},
 		},
 		"grant_types": &schema.Schema{
 			Type:     schema.TypeList,
 			Optional: true,
-			Elem:     &schema.Schema{Type: schema.TypeString},
+			Elem: &schema.Schema{
+				Type:         schema.TypeString,
+				ValidateFunc: oneOf(grantTypes),
+			},
 		},
 		"token_endpoint_auth_method": &schema.Schema{
-			Type:     schema.TypeString,
-			Optional: true,
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: oneOf(tokenEndpointAuthMethods),
 		},
 		"id_token_signed_response_alg": &schema.Schema{
-			Type:     schema.TypeString,
-			Required: true,
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: oneOf(idTokenSignedResponseAlgs),
 		},
 		"application_type": &schema.Schema{
-			Type:     schema.TypeString,
-			Required: true,
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: oneOf(applicationTypes),
 		},
 	}
 
@@ -57,11 +67,35 @@ func resourceIssuerClient() *schema.Resource {
 	}
 
 	generator := generator.Generator{
-		GetPath: getPath,
-		Client:  &api.HttpClient{},
-		Schema:  issuerClientSchema,
+		GetPath:       getPath,
+		Client:        &api.HttpClient{},
+		Schema:        issuerClientSchema,
+		CustomizeDiff: validateClientGrantTypes,
 	}
 
 	resource := generator.GenResource()
 	return &resource
 }
+
+// validateClientGrantTypes checks that the response types requested by an
+// OIDC client are backed by a matching grant type.
+func validateClientGrantTypes(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("response_types") || !d.NewValueKnown("grant_types") {
+		return nil
+	}
+
+	responseTypes := castToStringSlice(d.Get("response_types"))
+	grantTypes := castToStringSlice(d.Get("grant_types"))
+
+	if len(grantTypes) == 0 {
+		return nil
+	}
+
+	for _, responseType := range responseTypes {
+		if responseType == "code" && !containsString(grantTypes, "authorization_code") {
+			return fmt.Errorf("'grant_types' must include \"authorization_code\" when 'response_types' includes \"code\"")
+		}
+	}
+
+	return nil
+}
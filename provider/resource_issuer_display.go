@@ -0,0 +1,190 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"nz.antunovic/mattr-terraform-provider/api"
+	"nz.antunovic/mattr-terraform-provider/generator"
+)
+
+const credentialIssuerMetadataPath = "/.well-known/openid-credential-issuer"
+
+// resourceIssuerDisplay manages how wallets show the tenant as a credential
+// issuer, which is published in its OpenID4VCI credential issuer metadata
+func resourceIssuerDisplay(client api.Client) *schema.Resource {
+	write := func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		displays := make([]interface{}, 0)
+		for _, display := range d.Get("display").([]interface{}) {
+			displayMap := display.(map[string]interface{})
+			apiDisplay := map[string]interface{}{
+				"name": displayMap["name"],
+			}
+			for attribute, property := range issuerDisplayProperties {
+				if value, ok := displayMap[attribute].(string); ok && len(value) != 0 {
+					apiDisplay[property] = value
+				}
+			}
+			if logoUrl, ok := displayMap["logo_url"].(string); ok && len(logoUrl) != 0 {
+				logo := map[string]interface{}{"url": logoUrl}
+				if altText, ok := displayMap["logo_alt_text"].(string); ok && len(altText) != 0 {
+					logo["altText"] = altText
+				}
+				apiDisplay["logo"] = logo
+			}
+			displays = append(displays, apiDisplay)
+		}
+
+		configuration, err := putOpenidConfigurationProperty(ctx, client, m, "display", displays)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		d.SetId(openidConfigurationPath)
+		if err := setIssuerDisplay(d, configuration); err != nil {
+			return diag.FromErr(err)
+		}
+		return diag.FromErr(setCredentialIssuerMetadata(ctx, client, d, m))
+	}
+
+	read := func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		configuration, err := getOpenidConfiguration(ctx, client, m)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if configuration["display"] == nil {
+			d.SetId("")
+			return nil
+		}
+		if err := setIssuerDisplay(d, configuration); err != nil {
+			return diag.FromErr(err)
+		}
+		return diag.FromErr(setCredentialIssuerMetadata(ctx, client, d, m))
+	}
+
+	deleteDisplay := func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		if _, err := putOpenidConfigurationProperty(ctx, client, m, "display", nil); err != nil {
+			return diag.FromErr(err)
+		}
+		d.SetId("")
+		return nil
+	}
+
+	return &schema.Resource{
+		Description:   fmt.Sprintf("Configures how wallets display the tenant as a credential issuer, using %s", openidConfigurationPath),
+		CreateContext: write,
+		ReadContext:   read,
+		UpdateContext: write,
+		DeleteContext: deleteDisplay,
+		Schema: map[string]*schema.Schema{
+			"display": &schema.Schema{
+				Type:        schema.TypeList,
+				Required:    true,
+				Description: "How to display the issuer, with one block for each locale",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"locale": &schema.Schema{
+							Type:        schema.TypeString,
+							Optional:    true,
+							Description: "BCP 47 language tag, e.g. en-NZ",
+						},
+						"name": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"logo_url": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"logo_alt_text": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"background_color": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"text_color": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"credential_issuer_metadata": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: fmt.Sprintf("The %s document wallets read, as JSON", credentialIssuerMetadataPath),
+			},
+		},
+		// the metadata is only read after apply, so plans show it changing
+		CustomizeDiff: customdiff.ComputedIf("credential_issuer_metadata", func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) bool {
+			return d.HasChange("display")
+		}),
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(generator.DefaultTimeout),
+			Read:   schema.DefaultTimeout(generator.DefaultTimeout),
+			Update: schema.DefaultTimeout(generator.DefaultTimeout),
+			Delete: schema.DefaultTimeout(generator.DefaultTimeout),
+		},
+	}
+}
+
+// issuerDisplayProperties maps the display attributes that are sent as they
+// are to their names in the API
+var issuerDisplayProperties = map[string]string{
+	"locale":           "locale",
+	"background_color": "backgroundColor",
+	"text_color":       "textColor",
+}
+
+func setIssuerDisplay(d *schema.ResourceData, configuration map[string]interface{}) error {
+	apiDisplays, ok := configuration["display"].([]interface{})
+	if !ok {
+		return fmt.Errorf("Unexpected type for %s 'display' field: %T", openidConfigurationPath, configuration["display"])
+	}
+
+	displays := make([]interface{}, 0, len(apiDisplays))
+	for i, apiDisplay := range apiDisplays {
+		apiDisplayMap, ok := apiDisplay.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("Unexpected type for %s 'display' index %d: %T", openidConfigurationPath, i, apiDisplay)
+		}
+		display := map[string]interface{}{
+			"name": apiDisplayMap["name"],
+		}
+		for attribute, property := range issuerDisplayProperties {
+			display[attribute] = apiDisplayMap[property]
+		}
+		if logo, ok := apiDisplayMap["logo"].(map[string]interface{}); ok {
+			display["logo_url"] = logo["url"]
+			display["logo_alt_text"] = logo["altText"]
+		}
+		displays = append(displays, display)
+	}
+
+	return d.Set("display", displays)
+}
+
+func setCredentialIssuerMetadata(ctx context.Context, client api.Client, d *schema.ResourceData, m interface{}) error {
+	providerApi := m.(api.ProviderConfig).Api
+	url, err := providerApi.GetUrl(credentialIssuerMetadataPath)
+	if err != nil {
+		return err
+	}
+
+	// the metadata is public
+	response, err := client.Get(ctx, url, map[string]string{})
+	if err != nil {
+		return err
+	}
+	metadata, err := json.Marshal(response.Body)
+	if err != nil {
+		return fmt.Errorf("Unable to serialise %s: %s", credentialIssuerMetadataPath, err)
+	}
+	return d.Set("credential_issuer_metadata", string(metadata))
+}
@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestResourceIssuerDisplayCreate(t *testing.T) {
+	client := TestClient{
+		responses: map[string]interface{}{
+			"GET https://test.api/core/v1/openid/configuration": map[string]interface{}{
+				"credentials": []interface{}{"983c0a86-204f-4431-9371-f5a22e506599"},
+				"interactionHook": map[string]interface{}{
+					"url":    "https://app.example.com/hook",
+					"secret": "bWF0dHJzZWNyZXQ=",
+				},
+			},
+			"PUT https://test.api/core/v1/openid/configuration": map[string]interface{}{
+				"credentials": []interface{}{"983c0a86-204f-4431-9371-f5a22e506599"},
+				"display": []interface{}{
+					map[string]interface{}{
+						"name":            "Example Issuer",
+						"locale":          "en-NZ",
+						"backgroundColor": "#1A2B3C",
+						"logo": map[string]interface{}{
+							"url":     "https://example.com/logo.png",
+							"altText": "Example logo",
+						},
+					},
+				},
+			},
+			"GET https://test.api/.well-known/openid-credential-issuer": map[string]interface{}{
+				"credential_issuer": "https://test.api",
+			},
+		},
+	}
+
+	resource := resourceIssuerDisplay(&client)
+	resourceData := runCreate(t, resource, map[string]interface{}{
+		"display": []interface{}{
+			map[string]interface{}{
+				"name":             "Example Issuer",
+				"locale":           "en-NZ",
+				"background_color": "#1A2B3C",
+				"logo_url":         "https://example.com/logo.png",
+				"logo_alt_text":    "Example logo",
+			},
+		},
+	}, &client)
+
+	body := client.logs[1].body.(map[string]interface{})
+	AssertEqual(t, []interface{}{"983c0a86-204f-4431-9371-f5a22e506599"}, body["credentials"], "The rest of the configuration should be sent back unchanged")
+	AssertEqual(t, map[string]interface{}{
+		"url":    "https://app.example.com/hook",
+		"secret": "bWF0dHJzZWNyZXQ=",
+	}, body["interactionHook"], "The interaction hook should be sent back with its secret")
+	AssertEqual(t, []interface{}{
+		map[string]interface{}{
+			"name":            "Example Issuer",
+			"locale":          "en-NZ",
+			"backgroundColor": "#1A2B3C",
+			"logo": map[string]interface{}{
+				"url":     "https://example.com/logo.png",
+				"altText": "Example logo",
+			},
+		},
+	}, body["display"], "Display should be converted")
+
+	AssertEqual(t, "Example logo", resourceData.Get("display.0.logo_alt_text"), "Logo alt text should match")
+	AssertEqual(t, `{"credential_issuer":"https://test.api"}`, resourceData.Get("credential_issuer_metadata"), "Metadata should be exposed")
+	AssertEqual(t, map[string]string{}, client.logs[2].headers, "Metadata should be fetched without credentials")
+}
+
+func TestResourceIssuerDisplayDelete(t *testing.T) {
+	client := TestClient{
+		responses: map[string]interface{}{
+			"GET https://test.api/core/v1/openid/configuration": map[string]interface{}{
+				"credentials": []interface{}{"983c0a86-204f-4431-9371-f5a22e506599"},
+				"display":     []interface{}{map[string]interface{}{"name": "Example Issuer"}},
+			},
+			"PUT https://test.api/core/v1/openid/configuration": map[string]interface{}{
+				"credentials": []interface{}{"983c0a86-204f-4431-9371-f5a22e506599"},
+			},
+		},
+	}
+
+	resource := resourceIssuerDisplay(&client)
+	resourceData := resource.TestResourceData()
+	resourceData.SetId(openidConfigurationPath)
+
+	diags := resource.DeleteContext(context.Background(), resourceData, testProviderConfig())
+	if diags.HasError() {
+		t.Fatalf("Delete failed: %v", diags)
+	}
+
+	AssertEqual(t, map[string]interface{}{
+		"credentials": []interface{}{"983c0a86-204f-4431-9371-f5a22e506599"},
+	}, client.logs[1].body, "Only the display should be removed")
+}
+
+func TestResourceIssuerDisplayMetadataChangesWithDisplay(t *testing.T) {
+	resource := resourceIssuerDisplay(&TestClient{})
+	state := &terraform.InstanceState{
+		ID: openidConfigurationPath,
+		Attributes: map[string]string{
+			"id":                         openidConfigurationPath,
+			"display.#":                  "1",
+			"display.0.name":             "Example Issuer",
+			"credential_issuer_metadata": `{"credential_issuer":"https://test.api"}`,
+		},
+	}
+
+	diff, err := resource.Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"display": []interface{}{
+			map[string]interface{}{"name": "Renamed Issuer"},
+		},
+	}), testProviderConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff == nil || diff.Attributes["credential_issuer_metadata"] == nil || !diff.Attributes["credential_issuer_metadata"].NewComputed {
+		t.Fatal("Expected the metadata to be unknown until the new display is applied")
+	}
+}
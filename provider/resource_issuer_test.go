@@ -0,0 +1,56 @@
+package provider
+
+import (
+	"context"
+	"testing"
+)
+
+func TestResourceIssuerOpenidConfigurationUrl(t *testing.T) {
+	issuer := map[string]interface{}{
+		"id": "983c0a86-204f-4431-9371-f5a22e506599",
+		"credential": map[string]interface{}{
+			"issuerDid": "did:web:issuer.example.com",
+			"name":      "Example credential",
+			"context":   []interface{}{"https://schema.org"},
+			"type":      []interface{}{"ExampleCredential"},
+		},
+		"federatedProvider": map[string]interface{}{
+			"url":          "https://auth.example.com",
+			"clientId":     "client",
+			"clientSecret": "secret",
+		},
+		"claimMappings": []interface{}{
+			map[string]interface{}{"jsonLdTerm": "email", "oidcClaim": "email"},
+		},
+	}
+	client := TestClient{
+		responses: map[string]interface{}{
+			"POST https://test.api/ext/oidc/v1/issuers":                                     issuer,
+			"GET https://test.api/ext/oidc/v1/issuers/983c0a86-204f-4431-9371-f5a22e506599": issuer,
+		},
+	}
+
+	resource := resourceIssuer(&client)
+	resourceData := runCreate(t, resource, map[string]interface{}{
+		"issuer_did":    "did:web:issuer.example.com",
+		"name":          "Example credential",
+		"context":       []interface{}{"https://schema.org"},
+		"type":          []interface{}{"ExampleCredential"},
+		"url":           "https://auth.example.com",
+		"client_id":     "client",
+		"client_secret": "secret",
+		"claim_mappings": []interface{}{
+			map[string]interface{}{"json_ld_term": "email", "oidc_claim": "email"},
+		},
+	}, &client)
+
+	AssertEqual(t, "983c0a86-204f-4431-9371-f5a22e506599", resourceData.Id(), "ID should match")
+	AssertEqual(t, "https://test.api/ext/oidc/v1/issuers/983c0a86-204f-4431-9371-f5a22e506599/.well-known/openid-configuration", resourceData.Get("openid_configuration_url"), "OpenID configuration URL should be set")
+
+	// the URL is computed again on refresh, as it isn't in the response
+	resourceData.Set("openid_configuration_url", "")
+	if diags := resource.ReadContext(context.Background(), resourceData, testProviderConfig()); diags.HasError() {
+		t.Fatalf("Read failed: %v", diags)
+	}
+	AssertEqual(t, "https://test.api/ext/oidc/v1/issuers/983c0a86-204f-4431-9371-f5a22e506599/.well-known/openid-configuration", resourceData.Get("openid_configuration_url"), "OpenID configuration URL should be set on read")
+}
@@ -0,0 +1,233 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"nz.antunovic/mattr-terraform-provider/api"
+	"nz.antunovic/mattr-terraform-provider/generator"
+)
+
+const (
+	iacaPath           = "/v2/credentials/mobile/iacas"
+	documentSignerPath = "/v2/credentials/mobile/document-signers"
+)
+
+var countryCodePattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// resourceIaca manages an issuing authority certificate authority, the root
+// of trust for the tenant's mDocs
+func resourceIaca(client api.Client) *schema.Resource {
+	iacaSchema := mobileCertificateSchema()
+	iacaSchema["common_name"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Computed:    true,
+		ForceNew:    true,
+		Description: "Common name of the certificate's subject. Defaults to the tenant's domain",
+	}
+	iacaSchema["country"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Required:     true,
+		ForceNew:     true,
+		ValidateFunc: validation.StringMatch(countryCodePattern, "must be an ISO 3166-1 alpha-2 country code, e.g. NZ"),
+		Description:  "Country of the issuing authority, as an ISO 3166-1 alpha-2 code",
+	}
+
+	generator := generator.Generator{
+		Path:               iacaPath,
+		Client:             client,
+		Schema:             iacaSchema,
+		ModifyRequestBody:  withoutActive,
+		ModifyResponseBody: convertMobileCertificateRes,
+	}
+
+	resource := generator.GenResource()
+	withActivation(&resource, client, iacaPath)
+	resource.Description = fmt.Sprintf("Manages an IACA at %s. Set `active` to false to retire it", iacaPath)
+	return &resource
+}
+
+// resourceDocumentSignerCertificate manages a certificate, signed by an IACA,
+// that mDocs are signed with
+func resourceDocumentSignerCertificate(client api.Client) *schema.Resource {
+	documentSignerSchema := mobileCertificateSchema()
+	documentSignerSchema["iaca_id"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Required:    true,
+		ForceNew:    true,
+		Description: "ID of the IACA that signs this certificate",
+	}
+	documentSignerSchema["certificate_chain_pem"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The certificate followed by its IACA's certificate, in PEM format",
+	}
+
+	generator := generator.Generator{
+		Path:               documentSignerPath,
+		Client:             client,
+		Schema:             documentSignerSchema,
+		ModifyRequestBody:  withoutActive,
+		ModifyResponseBody: convertMobileCertificateRes,
+		// the chain needs the IACA's certificate, which is another request
+		AfterOperation: func(ctx context.Context, d *schema.ResourceData, m interface{}, response *api.Response) error {
+			return setCertificateChain(ctx, client, d, m)
+		},
+	}
+
+	resource := generator.GenResource()
+	withActivation(&resource, client, documentSignerPath)
+	resource.Description = fmt.Sprintf("Manages a document signer certificate at %s. Set `active` to false to retire it", documentSignerPath)
+
+	return &resource
+}
+
+func mobileCertificateSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"active": &schema.Schema{
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     true,
+			Description: "Whether the certificate is used for issuance. Set to false to retire it",
+		},
+		"certificate_pem": &schema.Schema{
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The certificate in PEM format",
+		},
+		"certificate_fingerprint": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"not_before": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"not_after": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+	}
+}
+
+func withoutActive(body interface{}) (interface{}, error) {
+	bodyMap, ok := body.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Unexpected type for certificate: %T", body)
+	}
+	// certificates are always created inactive, and activated separately
+	delete(bodyMap, "active")
+	return bodyMap, nil
+}
+
+func convertMobileCertificateRes(body interface{}) (interface{}, error) {
+	bodyMap, ok := body.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Unexpected type for certificate: %T", body)
+	}
+
+	if certificateData, ok := bodyMap["certificateData"].(map[string]interface{}); ok {
+		bodyMap["notBefore"] = certificateData["notBefore"]
+		bodyMap["notAfter"] = certificateData["notAfter"]
+		if commonName, ok := certificateData["commonName"]; ok {
+			bodyMap["commonName"] = commonName
+		}
+		if country, ok := certificateData["country"]; ok {
+			bodyMap["country"] = country
+		}
+	}
+	delete(bodyMap, "certificateData")
+
+	return bodyMap, nil
+}
+
+// withActivation changes `active` with its own update, because MATTR only
+// activates a certificate once it exists, and won't delete an active one
+func withActivation(resource *schema.Resource, client api.Client, path string) {
+	create := resource.CreateContext
+	read := resource.ReadContext
+	deleteCertificate := resource.DeleteContext
+
+	resource.CreateContext = func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		active := d.Get("active").(bool)
+		if diags := create(ctx, d, m); diags.HasError() {
+			return diags
+		}
+		if active == d.Get("active").(bool) {
+			return nil
+		}
+		if err := setCertificateActive(ctx, client, m, path, d.Id(), active); err != nil {
+			return diag.FromErr(err)
+		}
+		return read(ctx, d, m)
+	}
+
+	resource.UpdateContext = func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		if d.HasChange("active") {
+			if err := setCertificateActive(ctx, client, m, path, d.Id(), d.Get("active").(bool)); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+		return read(ctx, d, m)
+	}
+
+	resource.DeleteContext = func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		if d.Get("active").(bool) {
+			log.Printf("Retiring %s/%s before deleting it", path, d.Id())
+			if err := setCertificateActive(ctx, client, m, path, d.Id(), false); err != nil {
+				return diag.FromErr(err)
+			}
+		}
+		return deleteCertificate(ctx, d, m)
+	}
+}
+
+func setCertificateActive(ctx context.Context, client api.Client, m interface{}, path string, id string, active bool) error {
+	providerApi := m.(api.ProviderConfig).Api
+	url, err := providerApi.GetUrl(fmt.Sprintf("%s/%s", path, id))
+	if err != nil {
+		return err
+	}
+	headers, err := providerApi.AuthHeaders(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Put(ctx, url, headers, map[string]interface{}{
+		"active": active,
+	})
+	return err
+}
+
+func setCertificateChain(ctx context.Context, client api.Client, d *schema.ResourceData, m interface{}) error {
+	providerApi := m.(api.ProviderConfig).Api
+	url, err := providerApi.GetUrl(fmt.Sprintf("%s/%s", iacaPath, d.Get("iaca_id").(string)))
+	if err != nil {
+		return err
+	}
+	headers, err := providerApi.AuthHeaders(ctx)
+	if err != nil {
+		return err
+	}
+
+	response, err := client.Get(ctx, url, headers)
+	if err != nil {
+		return err
+	}
+	iaca, ok := response.Body.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("Unexpected type for %s: %T", iacaPath, response.Body)
+	}
+	iacaPem, _ := iaca["certificatePem"].(string)
+	certificatePem, _ := d.Get("certificate_pem").(string)
+
+	chain := strings.TrimSpace(certificatePem) + "\n" + strings.TrimSpace(iacaPem) + "\n"
+	return d.Set("certificate_chain_pem", chain)
+}
@@ -0,0 +1,121 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+const (
+	testIacaId  = "5b4a3d29-3a0f-4a4e-9c3f-0b1f2e3d4c5a"
+	testIacaPem = "-----BEGIN CERTIFICATE-----\nMIIBIACA\n-----END CERTIFICATE-----\n"
+	testDscPem  = "-----BEGIN CERTIFICATE-----\nMIIBDSC\n-----END CERTIFICATE-----\n"
+)
+
+func testIaca(active bool) map[string]interface{} {
+	return map[string]interface{}{
+		"id":                     testIacaId,
+		"active":                 active,
+		"certificatePem":         testIacaPem,
+		"certificateFingerprint": "3f1c7a8e",
+		"certificateData": map[string]interface{}{
+			"commonName": "example.com",
+			"country":    "NZ",
+			"notBefore":  "2024-01-01T00:00:00.000Z",
+			"notAfter":   "2034-01-01T00:00:00.000Z",
+		},
+	}
+}
+
+func TestResourceIacaCreateActivates(t *testing.T) {
+	client := TestClient{
+		responses: map[string]interface{}{
+			"POST https://test.api/v2/credentials/mobile/iacas":                 testIaca(false),
+			"PUT https://test.api/v2/credentials/mobile/iacas/" + testIacaId:    testIaca(true),
+			"GET https://test.api/v2/credentials/mobile/iacas/" + testIacaId:    testIaca(true),
+			"DELETE https://test.api/v2/credentials/mobile/iacas/" + testIacaId: nil,
+		},
+	}
+
+	resource := resourceIaca(&client)
+	resourceData := runCreate(t, resource, map[string]interface{}{
+		"country": "NZ",
+	}, &client)
+
+	AssertEqual(t, map[string]interface{}{"country": "NZ"}, client.logs[0].body, "IACA should be created without active")
+	AssertEqual(t, map[string]interface{}{"active": true}, client.logs[1].body, "IACA should be activated after creation")
+	AssertEqual(t, true, resourceData.Get("active"), "IACA should be active")
+	AssertEqual(t, "example.com", resourceData.Get("common_name"), "Common name should be read from the certificate")
+	AssertEqual(t, testIacaPem, resourceData.Get("certificate_pem"), "PEM should match")
+	AssertEqual(t, "2034-01-01T00:00:00.000Z", resourceData.Get("not_after"), "Expiry should match")
+
+	// an active IACA is retired before it is deleted
+	client.logs = nil
+	diags := resource.DeleteContext(context.Background(), resourceData, testProviderConfig())
+	if diags.HasError() {
+		t.Fatalf("Delete failed: %v", diags)
+	}
+	AssertEqual(t, "PUT", client.logs[0].method, "IACA should be retired first")
+	AssertEqual(t, "DELETE", client.logs[1].method, "IACA should then be deleted")
+}
+
+func TestResourceIacaRetire(t *testing.T) {
+	client := TestClient{
+		responses: map[string]interface{}{
+			"PUT https://test.api/v2/credentials/mobile/iacas/" + testIacaId: testIaca(false),
+			"GET https://test.api/v2/credentials/mobile/iacas/" + testIacaId: testIaca(false),
+		},
+	}
+
+	resource := resourceIaca(&client)
+	state := resource.Data(nil)
+	state.SetId(testIacaId)
+	state.Set("country", "NZ")
+	state.Set("active", true)
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"country": "NZ",
+		"active":  false,
+	})
+	diff, err := resource.Diff(context.Background(), state.State(), config, testProviderConfig())
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	newState, diags := resource.Apply(context.Background(), state.State(), diff, testProviderConfig())
+	if diags.HasError() {
+		t.Fatalf("Update failed: %v", diags)
+	}
+
+	AssertEqual(t, map[string]interface{}{"active": false}, client.logs[0].body, "Retiring should only change active")
+	AssertEqual(t, "false", newState.Attributes["active"], "IACA should be retired")
+}
+
+func TestResourceDocumentSignerCertificateChain(t *testing.T) {
+	client := TestClient{
+		responses: map[string]interface{}{
+			"POST https://test.api/v2/credentials/mobile/document-signers": map[string]interface{}{
+				"id":             "0d9b6f3e-6f1f-4c0b-8d1f-7e6f5d4c3b2a",
+				"iacaId":         testIacaId,
+				"active":         false,
+				"certificatePem": testDscPem,
+			},
+			"PUT https://test.api/v2/credentials/mobile/document-signers/0d9b6f3e-6f1f-4c0b-8d1f-7e6f5d4c3b2a": map[string]interface{}{},
+			"GET https://test.api/v2/credentials/mobile/document-signers/0d9b6f3e-6f1f-4c0b-8d1f-7e6f5d4c3b2a": map[string]interface{}{
+				"id":             "0d9b6f3e-6f1f-4c0b-8d1f-7e6f5d4c3b2a",
+				"iacaId":         testIacaId,
+				"active":         true,
+				"certificatePem": testDscPem,
+			},
+			"GET https://test.api/v2/credentials/mobile/iacas/" + testIacaId: testIaca(true),
+		},
+	}
+
+	resource := resourceDocumentSignerCertificate(&client)
+	resourceData := runCreate(t, resource, map[string]interface{}{
+		"iaca_id": testIacaId,
+	}, &client)
+
+	AssertEqual(t, true, resourceData.Get("active"), "Certificate should be active")
+	AssertEqual(t, testDscPem+testIacaPem, resourceData.Get("certificate_chain_pem"), "Chain should end with the IACA")
+}
@@ -0,0 +1,160 @@
+package provider
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"nz.antunovic/mattr-terraform-provider/api"
+	"nz.antunovic/mattr-terraform-provider/generator"
+)
+
+const mobilePresentationTemplatePath = "/v2/credentials/mobile/presentations/templates"
+
+// resourceMobilePresentationTemplate manages the mDocs, and which of their
+// elements, that a verifier application requests
+func resourceMobilePresentationTemplate(client api.Client) *schema.Resource {
+	mobilePresentationTemplateSchema := map[string]*schema.Schema{
+		"name": &schema.Schema{
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"request": &schema.Schema{
+			Type:     schema.TypeList,
+			Required: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"doctype": &schema.Schema{
+						Type:        schema.TypeString,
+						Required:    true,
+						Description: "Document type to request, e.g. org.iso.18013.5.1.mDL",
+					},
+					"element": &schema.Schema{
+						Type:     schema.TypeSet,
+						Required: true,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"namespace": &schema.Schema{
+									Type:     schema.TypeString,
+									Required: true,
+								},
+								"name": &schema.Schema{
+									Type:     schema.TypeString,
+									Required: true,
+								},
+								"intent_to_retain": &schema.Schema{
+									Type:        schema.TypeBool,
+									Optional:    true,
+									Description: "Tell the holder that the verifier will keep this element after the presentation",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	generator := generator.Generator{
+		Path:                  mobilePresentationTemplatePath,
+		Client:                client,
+		Schema:                mobilePresentationTemplateSchema,
+		ModifyRequestBody:     convertMobilePresentationTemplateReq,
+		ModifyResponseBody:    convertMobilePresentationTemplateRes,
+		OptimisticConcurrency: true,
+	}
+
+	resource := generator.GenResource()
+	return &resource
+}
+
+func convertMobilePresentationTemplateReq(body interface{}) (interface{}, error) {
+	bodyMap, ok := body.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Unexpected type for %s: %T", mobilePresentationTemplatePath, body)
+	}
+
+	requestList, _ := bodyMap["request"].([]interface{})
+	queries := make([]interface{}, 0, len(requestList))
+	for i, request := range requestList {
+		requestMap, ok := request.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Unexpected type for %s 'request' index %d: %T", mobilePresentationTemplatePath, i, request)
+		}
+
+		// elements are grouped by namespace
+		elementList, _ := requestMap["element"].([]interface{})
+		namespaces := make(map[string]interface{})
+		for _, element := range elementList {
+			elementMap, ok := element.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("Unexpected type for %s 'element' in request %d: %T", mobilePresentationTemplatePath, i, element)
+			}
+			namespace, _ := elementMap["namespace"].(string)
+			name, _ := elementMap["name"].(string)
+
+			namespaceMap, ok := namespaces[namespace].(map[string]interface{})
+			if !ok {
+				namespaceMap = make(map[string]interface{})
+				namespaces[namespace] = namespaceMap
+			}
+			intentToRetain, _ := elementMap["intentToRetain"].(bool)
+			namespaceMap[name] = map[string]interface{}{
+				"intentToRetain": intentToRetain,
+			}
+		}
+
+		queries = append(queries, map[string]interface{}{
+			"doctype":    requestMap["doctype"],
+			"namespaces": namespaces,
+		})
+	}
+	bodyMap["query"] = queries
+	delete(bodyMap, "request")
+
+	return bodyMap, nil
+}
+
+func convertMobilePresentationTemplateRes(body interface{}) (interface{}, error) {
+	bodyMap, ok := body.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Unexpected type for %s response: %T", mobilePresentationTemplatePath, body)
+	}
+
+	queryList, ok := bodyMap["query"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Unexpected type for %s 'query' field: %T", mobilePresentationTemplatePath, bodyMap["query"])
+	}
+	requests := make([]interface{}, 0, len(queryList))
+	for i, query := range queryList {
+		queryMap, ok := query.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Unexpected type for %s 'query' index %d: %T", mobilePresentationTemplatePath, i, query)
+		}
+
+		namespaces, _ := queryMap["namespaces"].(map[string]interface{})
+		elements := make([]interface{}, 0)
+		for namespace, namespaceElements := range namespaces {
+			namespaceMap, ok := namespaceElements.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("Unexpected type for %s 'namespaces'[%s] in query %d: %T", mobilePresentationTemplatePath, namespace, i, namespaceElements)
+			}
+			for name, element := range namespaceMap {
+				elementMap, _ := element.(map[string]interface{})
+				elements = append(elements, map[string]interface{}{
+					"namespace":      namespace,
+					"name":           name,
+					"intentToRetain": elementMap["intentToRetain"],
+				})
+			}
+		}
+
+		requests = append(requests, map[string]interface{}{
+			"doctype": queryMap["doctype"],
+			"element": elements,
+		})
+	}
+	bodyMap["request"] = requests
+	delete(bodyMap, "query")
+
+	return bodyMap, nil
+}
@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestResourceMobilePresentationTemplateCreate(t *testing.T) {
+	client := TestClient{
+		responses: map[string]interface{}{
+			"POST https://test.api/v2/credentials/mobile/presentations/templates": map[string]interface{}{
+				"id":   "9e8d7c6b-5a4f-4e3d-8c2b-1a0f9e8d7c6b",
+				"name": "Proof of age",
+				"query": []interface{}{
+					map[string]interface{}{
+						"doctype": "org.iso.18013.5.1.mDL",
+						"namespaces": map[string]interface{}{
+							"org.iso.18013.5.1": map[string]interface{}{
+								"age_over_18": map[string]interface{}{"intentToRetain": false},
+								"portrait":    map[string]interface{}{"intentToRetain": true},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	resource := resourceMobilePresentationTemplate(&client)
+	resourceData := runCreate(t, resource, map[string]interface{}{
+		"name": "Proof of age",
+		"request": []interface{}{
+			map[string]interface{}{
+				"doctype": "org.iso.18013.5.1.mDL",
+				"element": []interface{}{
+					map[string]interface{}{"namespace": "org.iso.18013.5.1", "name": "age_over_18"},
+					map[string]interface{}{"namespace": "org.iso.18013.5.1", "name": "portrait", "intent_to_retain": true},
+				},
+			},
+		},
+	}, &client)
+
+	query := client.logs[0].body.(map[string]interface{})["query"].([]interface{})[0].(map[string]interface{})
+	AssertEqual(t, "org.iso.18013.5.1.mDL", query["doctype"], "Doctype should be sent")
+	AssertEqual(t, map[string]interface{}{
+		"org.iso.18013.5.1": map[string]interface{}{
+			"age_over_18": map[string]interface{}{"intentToRetain": false},
+			"portrait":    map[string]interface{}{"intentToRetain": true},
+		},
+	}, query["namespaces"], "Elements should be grouped by namespace")
+
+	AssertEqual(t, "org.iso.18013.5.1.mDL", resourceData.Get("request.0.doctype"), "Doctype should be read back")
+	AssertEqual(t, 2, resourceData.Get("request.0.element").(*schema.Set).Len(), "Both elements should be read back")
+}
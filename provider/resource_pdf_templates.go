@@ -13,6 +13,7 @@ import (
 	"net/url"
 	"nz.antunovic/mattr-terraform-provider/api"
 	"nz.antunovic/mattr-terraform-provider/generator"
+	"time"
 )
 
 type ZipCreator struct {
@@ -90,6 +91,11 @@ func (z *ZipCreator) writeConfig(config interface{}) error {
 func templateGenerator() generator.Generator {
 	generator := generator.Generator{
 		Client: &api.HttpClient{},
+		// uploads include the PDF and every font, which can be several megabytes
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+		},
 		Schema: map[string]*schema.Schema{
 			"template_path": &schema.Schema{
 				Type:     schema.TypeString,
@@ -1,15 +1,19 @@
 package provider
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"nz.antunovic/mattr-terraform-provider/api"
 	"nz.antunovic/mattr-terraform-provider/generator"
 )
 
-func resourcePresentation() *schema.Resource {
+const presentationPath = "/v2/credentials/web-semantic/presentations/templates"
+
+func resourcePresentation(client api.Client) *schema.Resource {
 	presentationSchema := map[string]*schema.Schema{
 		"domain": &schema.Schema{
 			Type:     schema.TypeString,
@@ -20,8 +24,10 @@ func resourcePresentation() *schema.Resource {
 			Required: true,
 		},
 		"query": &schema.Schema{
-			Type:     schema.TypeList,
-			Required: true,
+			Type:          schema.TypeList,
+			Optional:      true,
+			ConflictsWith: typedPresentationQueries,
+			Description:   "Queries as sent to MATTR, for anything the typed query blocks can't express. When imported, queries the typed blocks can express are read into them instead.",
 			Elem: &schema.Resource{
 				Schema: map[string]*schema.Schema{
 					"type": &schema.Schema{
@@ -30,7 +36,7 @@ func resourcePresentation() *schema.Resource {
 					},
 					"credential_query": &schema.Schema{
 						Type:     schema.TypeList,
-						Required: true,
+						Optional: true,
 						Elem: &schema.Resource{
 							Schema: map[string]*schema.Schema{
 								"required": &schema.Schema{
@@ -41,58 +47,336 @@ func resourcePresentation() *schema.Resource {
 									Type:     schema.TypeString,
 									Required: true,
 								},
-								"trusted_issuer": &schema.Schema{
-									Type:     schema.TypeList,
-									Optional: true,
-									Elem: &schema.Resource{
-										Schema: map[string]*schema.Schema{
-											"required": &schema.Schema{
-												Type:     schema.TypeBool,
-												Required: true,
-											},
-											"issuer": &schema.Schema{
-												Type:     schema.TypeString,
-												Required: true,
-											},
-										},
-									},
-								},
-								"frame": &schema.Schema{
+								"trusted_issuer": trustedIssuerQuerySchema(),
+								"frame": generator.JsonAttribute(&schema.Schema{
 									Type:     schema.TypeString,
 									Optional: true,
-								},
-								"example": &schema.Schema{
+								}),
+								"example": generator.JsonAttribute(&schema.Schema{
 									Type:     schema.TypeString,
 									Optional: true,
-								},
+								}),
 							},
 						},
 					},
 				},
 			},
 		},
+		"query_by_example": &schema.Schema{
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "Requests credentials that match an example, in a QueryByExample query",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"required": &schema.Schema{
+						Type:     schema.TypeBool,
+						Optional: true,
+						Default:  true,
+					},
+					"reason": &schema.Schema{
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"type": &schema.Schema{
+						Type:        schema.TypeList,
+						Required:    true,
+						MinItems:    1,
+						Elem:        &schema.Schema{Type: schema.TypeString},
+						Description: "Types of the credential, e.g. [\"VerifiableCredential\", \"AlumniCredential\"]",
+					},
+					"context": &schema.Schema{
+						Type:        schema.TypeList,
+						Required:    true,
+						MinItems:    1,
+						Elem:        &schema.Schema{Type: schema.TypeString},
+						Description: "JSON-LD contexts of the credential",
+					},
+					"trusted_issuer": trustedIssuerQuerySchema(),
+				},
+			},
+		},
+		"query_by_frame": &schema.Schema{
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "Requests credentials, or selected claims of them, with a JSON-LD frame in a QueryByFrame query",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"required": &schema.Schema{
+						Type:     schema.TypeBool,
+						Optional: true,
+						Default:  true,
+					},
+					"reason": &schema.Schema{
+						Type:     schema.TypeString,
+						Required: true,
+					},
+					"frame": generator.JsonAttribute(&schema.Schema{
+						Type:     schema.TypeString,
+						Required: true,
+					}),
+					"trusted_issuer": trustedIssuerQuerySchema(),
+				},
+			},
+		},
+		"did_auth": &schema.Schema{
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Asks the holder to authenticate with their DID, in a DIDAuth query",
+		},
 	}
 
 	generator := generator.Generator{
-		Path:               "/v2/credentials/web-semantic/presentations/templates",
-		Client:             &api.HttpClient{},
+		Path:               presentationPath,
+		Client:             client,
 		Schema:             presentationSchema,
 		ModifyRequestBody:  modifyRequestBody,
 		ModifyResponseBody: modifyResponseBody,
+		CustomizeDiff:      validatePresentationQuery,
 	}
 
 	resource := generator.GenResource()
+	withTypedPresentationQueries(&resource)
 	return &resource
 }
 
+// typedPresentationQueries are the attributes that each model one type of
+// query, as an alternative to `query`
+var typedPresentationQueries = []string{"query_by_example", "query_by_frame", "did_auth"}
+
+func trustedIssuerQuerySchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"required": &schema.Schema{
+					Type:     schema.TypeBool,
+					Required: true,
+				},
+				"issuer": &schema.Schema{
+					Type:     schema.TypeString,
+					Required: true,
+				},
+			},
+		},
+	}
+}
+
 func modifyRequestBody(body interface{}) (interface{}, error) {
-	return transformBody(body, true)
+	body, err := transformBody(body, true)
+	if err != nil {
+		return nil, err
+	}
+	return convertTypedPresentationQueriesReq(body.(map[string]interface{}))
 }
 
 func modifyResponseBody(body interface{}) (interface{}, error) {
 	return transformBody(body, false)
 }
 
+// convertTypedPresentationQueriesReq turns the typed query blocks into one
+// query of each type
+func convertTypedPresentationQueriesReq(bodyMap map[string]interface{}) (interface{}, error) {
+	queries := make([]interface{}, 0)
+
+	if examples, ok := bodyMap["queryByExample"].([]interface{}); ok {
+		credentialQueries := make([]interface{}, 0, len(examples))
+		for i, example := range examples {
+			exampleMap, ok := example.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("Unexpected type for %s 'query_by_example' index %d: %T", presentationPath, i, example)
+			}
+			// a single type is sent on its own, as MATTR documents it
+			credentialType := exampleMap["type"]
+			if types, ok := credentialType.([]interface{}); ok && len(types) == 1 {
+				credentialType = types[0]
+			}
+			apiExample := map[string]interface{}{
+				"@context": exampleMap["context"],
+				"type":     credentialType,
+			}
+			if trustedIssuer, ok := exampleMap["trustedIssuer"]; ok {
+				apiExample["trustedIssuer"] = trustedIssuer
+			}
+			credentialQueries = append(credentialQueries, map[string]interface{}{
+				"required": exampleMap["required"],
+				"reason":   exampleMap["reason"],
+				"example":  apiExample,
+			})
+		}
+		queries = append(queries, map[string]interface{}{
+			"type":            "QueryByExample",
+			"credentialQuery": credentialQueries,
+		})
+	}
+
+	if frames, ok := bodyMap["queryByFrame"].([]interface{}); ok {
+		credentialQueries := make([]interface{}, 0, len(frames))
+		for i, frame := range frames {
+			frameMap, ok := frame.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("Unexpected type for %s 'query_by_frame' index %d: %T", presentationPath, i, frame)
+			}
+			frameJson, ok := frameMap["frame"].(string)
+			if !ok {
+				return nil, fmt.Errorf("Unexpected type for %s 'query_by_frame' index %d 'frame' field: %T", presentationPath, i, frameMap["frame"])
+			}
+			var payload json.RawMessage
+			if err := json.Unmarshal([]byte(frameJson), &payload); err != nil {
+				return nil, fmt.Errorf("Error parsing JSON for 'frame' field (check your syntax): %s", err)
+			}
+			frameMap["frame"] = payload
+			credentialQueries = append(credentialQueries, frameMap)
+		}
+		queries = append(queries, map[string]interface{}{
+			"type":            "QueryByFrame",
+			"credentialQuery": credentialQueries,
+		})
+	}
+
+	if didAuth, _ := bodyMap["didAuth"].(bool); didAuth {
+		queries = append(queries, map[string]interface{}{
+			"type": "DIDAuth",
+		})
+	}
+
+	delete(bodyMap, "queryByExample")
+	delete(bodyMap, "queryByFrame")
+	delete(bodyMap, "didAuth")
+	if len(queries) != 0 {
+		bodyMap["query"] = queries
+	}
+
+	return bodyMap, nil
+}
+
+// withTypedPresentationQueries reads the queries back into the typed query
+// blocks after each operation, including on import. Only a configuration or
+// state that already has `query` keeps it, so it doesn't churn between forms.
+func withTypedPresentationQueries(resource *schema.Resource) {
+	wrap := func(operation func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+		return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+			usesQuery := len(d.Get("query").([]interface{})) != 0
+			if diags := operation(ctx, d, m); diags.HasError() || usesQuery || len(d.Id()) == 0 {
+				return diags
+			}
+			return diag.FromErr(setTypedPresentationQueries(d))
+		}
+	}
+
+	resource.CreateContext = wrap(resource.CreateContext)
+	resource.ReadContext = wrap(resource.ReadContext)
+	resource.UpdateContext = wrap(resource.UpdateContext)
+}
+
+// setTypedPresentationQueries moves the queries into the typed query blocks
+// if they can all be expressed that way, and leaves them in `query` otherwise
+func setTypedPresentationQueries(d *schema.ResourceData) error {
+	queries := d.Get("query").([]interface{})
+	for _, query := range queries {
+		switch query.(map[string]interface{})["type"] {
+		case "QueryByExample", "QueryByFrame", "DIDAuth":
+		default:
+			return clearTypedPresentationQueries(d)
+		}
+	}
+
+	examples := make([]interface{}, 0)
+	frames := make([]interface{}, 0)
+	didAuth := false
+	for _, query := range queries {
+		queryMap := query.(map[string]interface{})
+		credentialQueries, _ := queryMap["credential_query"].([]interface{})
+
+		switch queryMap["type"] {
+		case "QueryByExample":
+			for _, credentialQuery := range credentialQueries {
+				example, err := convertQueryByExampleRes(credentialQuery.(map[string]interface{}))
+				if err != nil {
+					return err
+				}
+				examples = append(examples, example)
+			}
+		case "QueryByFrame":
+			for _, credentialQuery := range credentialQueries {
+				credentialQueryMap := credentialQuery.(map[string]interface{})
+				frames = append(frames, map[string]interface{}{
+					"required":       credentialQueryMap["required"],
+					"reason":         credentialQueryMap["reason"],
+					"frame":          credentialQueryMap["frame"],
+					"trusted_issuer": credentialQueryMap["trusted_issuer"],
+				})
+			}
+		case "DIDAuth":
+			didAuth = true
+		}
+	}
+
+	if err := d.Set("query_by_example", examples); err != nil {
+		return err
+	}
+	if err := d.Set("query_by_frame", frames); err != nil {
+		return err
+	}
+	if err := d.Set("did_auth", didAuth); err != nil {
+		return err
+	}
+	return d.Set("query", nil)
+}
+
+func clearTypedPresentationQueries(d *schema.ResourceData) error {
+	for _, attribute := range typedPresentationQueries {
+		if err := d.Set(attribute, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func convertQueryByExampleRes(credentialQuery map[string]interface{}) (map[string]interface{}, error) {
+	exampleJson, _ := credentialQuery["example"].(string)
+	var apiExample struct {
+		Context       []interface{} `json:"@context"`
+		Type          interface{}   `json:"type"`
+		TrustedIssuer []struct {
+			Required bool   `json:"required"`
+			Issuer   string `json:"issuer"`
+		} `json:"trustedIssuer"`
+	}
+	if err := json.Unmarshal([]byte(exampleJson), &apiExample); err != nil {
+		return nil, fmt.Errorf("Unable to parse QueryByExample example: %s", err)
+	}
+
+	// the type can be a single type or a list of them
+	var credentialTypes []interface{}
+	switch credentialType := apiExample.Type.(type) {
+	case []interface{}:
+		credentialTypes = credentialType
+	case string:
+		credentialTypes = []interface{}{credentialType}
+	case nil:
+		credentialTypes = []interface{}{}
+	default:
+		return nil, fmt.Errorf("Unexpected type for QueryByExample example 'type' field: %T", credentialType)
+	}
+
+	trustedIssuers := make([]interface{}, 0, len(apiExample.TrustedIssuer))
+	for _, trustedIssuer := range apiExample.TrustedIssuer {
+		trustedIssuers = append(trustedIssuers, map[string]interface{}{
+			"required": trustedIssuer.Required,
+			"issuer":   trustedIssuer.Issuer,
+		})
+	}
+
+	return map[string]interface{}{
+		"required":       credentialQuery["required"],
+		"reason":         credentialQuery["reason"],
+		"type":           credentialTypes,
+		"context":        apiExample.Context,
+		"trusted_issuer": trustedIssuers,
+	}, nil
+}
+
 // TODO: fix this monstrosity
 func transformBody(body interface{}, isRequest bool) (interface{}, error) {
 	bodyMap, ok := body.(map[string]interface{})
@@ -100,6 +384,11 @@ func transformBody(body interface{}, isRequest bool) (interface{}, error) {
 		return nil, fmt.Errorf("Unexpected type for /v2/credentials/web-semantic/presentations/templates response: %T", body)
 	}
 
+	// typed query blocks are sent instead of 'query'
+	if bodyMap["query"] == nil && isRequest {
+		return bodyMap, nil
+	}
+
 	bodyQueryList, ok := bodyMap["query"].([]interface{})
 	if !ok {
 		return nil, fmt.Errorf("Unexpected type for /v2/credentials/web-semantic/presentations/templates 'query' field: %T", bodyQueryList)
@@ -111,6 +400,11 @@ func transformBody(body interface{}, isRequest bool) (interface{}, error) {
 			return nil, fmt.Errorf("Unexpected type for /v2/credentials/web-semantic/presentations/templates 'query' index %d: %T", j, bodyQueryList)
 		}
 
+		// DIDAuth queries have no credential queries
+		if bodyQueryMap["credentialQuery"] == nil {
+			continue
+		}
+
 		credentialQuery, ok := bodyQueryMap["credentialQuery"].([]interface{})
 		if !ok {
 			return nil, fmt.Errorf("Unexpected type for /v2/credentials/web-semantic/presentations/templates 'credentialQuery' field: %T", credentialQuery)
@@ -166,3 +460,18 @@ func transformBody(body interface{}, isRequest bool) (interface{}, error) {
 
 	return bodyMap, nil
 }
+
+// validatePresentationQuery requires a presentation template to have at
+// least one query, in either form
+func validatePresentationQuery(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	for _, attribute := range append([]string{"query"}, typedPresentationQueries...) {
+		if !d.NewValueKnown(attribute) {
+			return nil
+		}
+		if _, ok := d.GetOk(attribute); ok {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("One of 'query', 'query_by_example', 'query_by_frame' or 'did_auth' is required")
+}
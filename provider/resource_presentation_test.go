@@ -0,0 +1,318 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func presentationConfig(frame string) map[string]interface{} {
+	return map[string]interface{}{
+		"domain": "example.com",
+		"name":   "Example presentation",
+		"query": []interface{}{
+			map[string]interface{}{
+				"type": "QueryByFrame",
+				"credential_query": []interface{}{
+					map[string]interface{}{
+						"required": true,
+						"reason":   "To check your membership",
+						"frame":    frame,
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestResourcePresentationFrameIsSemanticallyCompared(t *testing.T) {
+	client := TestClient{
+		responses: map[string]interface{}{
+			"POST https://test.api/v2/credentials/web-semantic/presentations/templates": map[string]interface{}{
+				"id":     "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d",
+				"domain": "example.com",
+				"name":   "Example presentation",
+				"query": []interface{}{
+					map[string]interface{}{
+						"type": "QueryByFrame",
+						"credentialQuery": []interface{}{
+							map[string]interface{}{
+								"required": true,
+								"reason":   "To check your membership",
+								"frame": map[string]interface{}{
+									"type":     "MembershipCredential",
+									"@context": []interface{}{"https://www.w3.org/2018/credentials/v1"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	resource := resourcePresentation(&client)
+	frame := `{"type": "MembershipCredential", "@context": ["https://www.w3.org/2018/credentials/v1"]}`
+	state := runCreate(t, resource, presentationConfig(frame), &client).State()
+
+	AssertEqual(t, `{"@context":["https://www.w3.org/2018/credentials/v1"],"type":"MembershipCredential"}`, state.Attributes["query.0.credential_query.0.frame"], "Frame should be normalised")
+
+	reformatted := `{
+  "@context": [
+    "https://www.w3.org/2018/credentials/v1"
+  ],
+  "type": "MembershipCredential"
+}`
+	diff, err := resource.Diff(context.Background(), state, terraform.NewResourceConfigRaw(presentationConfig(reformatted)), testProviderConfig())
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if !diff.Empty() {
+		t.Errorf("Reformatting the frame should not be a change: %v", diff.Attributes)
+	}
+}
+
+func TestResourcePresentationFrameMustBeJson(t *testing.T) {
+	resource := resourcePresentation(&TestClient{})
+	config := terraform.NewResourceConfigRaw(presentationConfig(`{"type": `))
+
+	if diags := resource.Validate(config); !diags.HasError() {
+		t.Errorf("Invalid JSON should fail validation")
+	}
+}
+
+func TestResourcePresentationTypedQueries(t *testing.T) {
+	query := []interface{}{
+		map[string]interface{}{
+			"type": "QueryByExample",
+			"credentialQuery": []interface{}{
+				map[string]interface{}{
+					"required": true,
+					"reason":   "To check your degree",
+					"example": map[string]interface{}{
+						"@context": []interface{}{"https://www.w3.org/2018/credentials/v1"},
+						"type":     "AlumniCredential",
+						"trustedIssuer": []interface{}{
+							map[string]interface{}{"required": true, "issuer": "did:web:university.example.com"},
+						},
+					},
+				},
+			},
+		},
+		map[string]interface{}{
+			"type": "QueryByFrame",
+			"credentialQuery": []interface{}{
+				map[string]interface{}{
+					"required": false,
+					"reason":   "To check your membership",
+					"frame":    map[string]interface{}{"type": "MembershipCredential"},
+				},
+			},
+		},
+		map[string]interface{}{
+			"type": "DIDAuth",
+		},
+	}
+	// serialised before the response is converted, which changes it in place
+	expected, _ := json.Marshal(map[string]interface{}{
+		"domain": "example.com",
+		"name":   "Example presentation",
+		"query":  query,
+	})
+	client := TestClient{
+		responses: map[string]interface{}{
+			"POST https://test.api/v2/credentials/web-semantic/presentations/templates": map[string]interface{}{
+				"id":     "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d",
+				"domain": "example.com",
+				"name":   "Example presentation",
+				"query":  query,
+			},
+		},
+	}
+
+	resource := resourcePresentation(&client)
+	resourceData := runCreate(t, resource, map[string]interface{}{
+		"domain": "example.com",
+		"name":   "Example presentation",
+		"query_by_example": []interface{}{
+			map[string]interface{}{
+				"required": true,
+				"reason":   "To check your degree",
+				"type":     []interface{}{"AlumniCredential"},
+				"context":  []interface{}{"https://www.w3.org/2018/credentials/v1"},
+				"trusted_issuer": []interface{}{
+					map[string]interface{}{"required": true, "issuer": "did:web:university.example.com"},
+				},
+			},
+		},
+		"query_by_frame": []interface{}{
+			map[string]interface{}{
+				"required": false,
+				"reason":   "To check your membership",
+				"frame":    `{"type": "MembershipCredential"}`,
+			},
+		},
+		"did_auth": true,
+	}, &client)
+
+	body, err := json.Marshal(client.logs[0].body)
+	if err != nil {
+		t.Fatalf("Unable to serialise request: %s", err)
+	}
+	AssertEqual(t, string(expected), string(body), "Typed queries should be sent as one query of each type")
+
+	AssertEqual(t, 0, len(resourceData.Get("query").([]interface{})), "Raw queries should not be set")
+	AssertEqual(t, []interface{}{"AlumniCredential"}, resourceData.Get("query_by_example.0.type"), "Example type should be read back")
+	AssertEqual(t, "did:web:university.example.com", resourceData.Get("query_by_example.0.trusted_issuer.0.issuer"), "Trusted issuer should be read back")
+	AssertEqual(t, `{"type":"MembershipCredential"}`, resourceData.Get("query_by_frame.0.frame"), "Frame should be read back")
+	AssertEqual(t, true, resourceData.Get("did_auth"), "DIDAuth should be read back")
+}
+
+func TestResourcePresentationImportUsesTypedQueries(t *testing.T) {
+	client := TestClient{
+		responses: map[string]interface{}{
+			"GET https://test.api/v2/credentials/web-semantic/presentations/templates/a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d": map[string]interface{}{
+				"id":     "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d",
+				"domain": "example.com",
+				"name":   "Example presentation",
+				"query": []interface{}{
+					map[string]interface{}{
+						"type": "QueryByFrame",
+						"credentialQuery": []interface{}{
+							map[string]interface{}{
+								"required": true,
+								"reason":   "To check your membership",
+								"frame":    map[string]interface{}{"type": "MembershipCredential"},
+							},
+						},
+					},
+					map[string]interface{}{
+						"type": "DIDAuth",
+					},
+				},
+			},
+		},
+	}
+
+	// an imported resource has nothing in state but its ID
+	resource := resourcePresentation(&client)
+	resourceData := resource.TestResourceData()
+	resourceData.SetId("a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d")
+	if diags := resource.ReadContext(context.Background(), resourceData, testProviderConfig()); diags.HasError() {
+		t.Fatalf("Read failed: %v", diags)
+	}
+
+	AssertEqual(t, 0, len(resourceData.Get("query").([]interface{})), "Queries the typed blocks can express should not be left in 'query'")
+	AssertEqual(t, `{"type":"MembershipCredential"}`, resourceData.Get("query_by_frame.0.frame"), "Frame should be read into the typed block")
+	AssertEqual(t, true, resourceData.Get("did_auth"), "DIDAuth should be read into the typed block")
+}
+
+func TestResourcePresentationImportKeepsUntypedQueries(t *testing.T) {
+	client := TestClient{
+		responses: map[string]interface{}{
+			"GET https://test.api/v2/credentials/web-semantic/presentations/templates/a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d": map[string]interface{}{
+				"id":     "a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d",
+				"domain": "example.com",
+				"name":   "Example presentation",
+				"query": []interface{}{
+					map[string]interface{}{
+						"type": "DIDAuth",
+					},
+					map[string]interface{}{
+						"type": "QueryByNewThing",
+					},
+				},
+			},
+		},
+	}
+
+	resource := resourcePresentation(&client)
+	resourceData := resource.TestResourceData()
+	resourceData.SetId("a1b2c3d4-e5f6-4a7b-8c9d-0e1f2a3b4c5d")
+	if diags := resource.ReadContext(context.Background(), resourceData, testProviderConfig()); diags.HasError() {
+		t.Fatalf("Read failed: %v", diags)
+	}
+
+	AssertEqual(t, 2, len(resourceData.Get("query").([]interface{})), "All queries should stay in 'query' when one has no typed block")
+	AssertEqual(t, false, resourceData.Get("did_auth"), "Typed blocks should not be set")
+}
+
+func TestConvertQueryByExampleResKeepsAllTypes(t *testing.T) {
+	example, err := convertQueryByExampleRes(map[string]interface{}{
+		"required": true,
+		"reason":   "To check your degree",
+		"example":  `{"@context":["https://www.w3.org/2018/credentials/v1"],"type":["VerifiableCredential","AlumniCredential"]}`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	AssertEqual(t, []interface{}{"VerifiableCredential", "AlumniCredential"}, example["type"], "Every type should be kept")
+}
+
+func TestConvertTypedPresentationQueriesReqMultipleTypes(t *testing.T) {
+	body, err := convertTypedPresentationQueriesReq(map[string]interface{}{
+		"queryByExample": []interface{}{
+			map[string]interface{}{
+				"required": true,
+				"reason":   "To check your degree",
+				"type":     []interface{}{"VerifiableCredential", "AlumniCredential"},
+				"context":  []interface{}{"https://www.w3.org/2018/credentials/v1"},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := body.(map[string]interface{})["query"].([]interface{})[0].(map[string]interface{})
+	example := query["credentialQuery"].([]interface{})[0].(map[string]interface{})["example"].(map[string]interface{})
+	AssertEqual(t, []interface{}{"VerifiableCredential", "AlumniCredential"}, example["type"], "Every type should be sent")
+}
+
+func TestConvertTypedPresentationQueriesReqRejectsNonStringFrame(t *testing.T) {
+	_, err := convertTypedPresentationQueriesReq(map[string]interface{}{
+		"queryByFrame": []interface{}{
+			map[string]interface{}{
+				"reason": "To check your membership",
+				"frame":  map[string]interface{}{"type": "MembershipCredential"},
+			},
+		},
+	})
+	if err == nil {
+		t.Fatal("Expected an error for a frame that isn't a JSON string")
+	}
+}
+
+func TestResourcePresentationRequiresQuery(t *testing.T) {
+	resource := resourcePresentation(&TestClient{})
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"domain": "example.com",
+		"name":   "Example presentation",
+	})
+	if _, err := resource.Diff(context.Background(), nil, config, nil); err == nil {
+		t.Errorf("A template without queries should be rejected")
+	}
+
+	config = terraform.NewResourceConfigRaw(map[string]interface{}{
+		"domain":   "example.com",
+		"name":     "Example presentation",
+		"did_auth": true,
+	})
+	if _, err := resource.Diff(context.Background(), nil, config, nil); err != nil {
+		t.Errorf("A DIDAuth query on its own should be accepted: %s", err)
+	}
+
+	config = terraform.NewResourceConfigRaw(map[string]interface{}{
+		"domain":   "example.com",
+		"name":     "Example presentation",
+		"did_auth": true,
+		"query": []interface{}{
+			map[string]interface{}{"type": "DIDAuth"},
+		},
+	})
+	if diags := resource.Validate(config); !diags.HasError() {
+		t.Errorf("Typed queries should conflict with 'query'")
+	}
+}
@@ -0,0 +1,118 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"nz.antunovic/mattr-terraform-provider/api"
+	"nz.antunovic/mattr-terraform-provider/generator"
+)
+
+// resourceCredentialRevocation sets the revocation status of a web credential
+// issued by the tenant. Destroying it un-revokes the credential.
+func resourceCredentialRevocation(client api.Client) *schema.Resource {
+	setStatus := func(ctx context.Context, d *schema.ResourceData, m interface{}, revoked bool) error {
+		providerApi := m.(api.ProviderConfig).Api
+		url, err := providerApi.GetUrl(revocationStatusPath(d.Get("credential_id").(string)))
+		if err != nil {
+			return err
+		}
+		headers, err := providerApi.AuthHeaders(ctx)
+		if err != nil {
+			return err
+		}
+
+		_, err = client.Post(ctx, url, headers, map[string]interface{}{
+			"isRevoked": revoked,
+		})
+		return err
+	}
+
+	read := func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		providerApi := m.(api.ProviderConfig).Api
+		url, err := providerApi.GetUrl(revocationStatusPath(d.Get("credential_id").(string)))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		headers, err := providerApi.AuthHeaders(ctx)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		response, err := client.Get(ctx, url, headers)
+		var apiError api.ApiError
+		if errors.As(err, &apiError) && apiError.StatusCode == 404 {
+			log.Printf("Credential %s no longer exists", d.Id())
+			d.SetId("")
+			return nil
+		}
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		status, ok := response.Body.(map[string]interface{})
+		if !ok {
+			return diag.Errorf("Unexpected type for revocation status of %s: %T", d.Id(), response.Body)
+		}
+		return diag.FromErr(d.Set("revoked", status["isRevoked"]))
+	}
+
+	create := func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		if err := setStatus(ctx, d, m, d.Get("revoked").(bool)); err != nil {
+			return diag.FromErr(err)
+		}
+		d.SetId(d.Get("credential_id").(string))
+		return read(ctx, d, m)
+	}
+
+	update := func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		if err := setStatus(ctx, d, m, d.Get("revoked").(bool)); err != nil {
+			return diag.FromErr(err)
+		}
+		return read(ctx, d, m)
+	}
+
+	deleteRevocation := func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		if err := setStatus(ctx, d, m, false); err != nil {
+			return diag.FromErr(err)
+		}
+		d.SetId("")
+		return nil
+	}
+
+	return &schema.Resource{
+		Description:   "Revokes or un-revokes a web credential using /v2/credentials/web-semantic/{id}/revocation-status. Destroying it un-revokes the credential",
+		CreateContext: create,
+		ReadContext:   read,
+		UpdateContext: update,
+		DeleteContext: deleteRevocation,
+		Schema: map[string]*schema.Schema{
+			"credential_id": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the credential, which must have been issued as revocable",
+			},
+			"revoked": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether the credential is revoked",
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(generator.DefaultTimeout),
+			Read:   schema.DefaultTimeout(generator.DefaultTimeout),
+			Update: schema.DefaultTimeout(generator.DefaultTimeout),
+			Delete: schema.DefaultTimeout(generator.DefaultTimeout),
+		},
+	}
+}
+
+func revocationStatusPath(credentialId string) string {
+	return fmt.Sprintf("/v2/credentials/web-semantic/%s/revocation-status", credentialId)
+}
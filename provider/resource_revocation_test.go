@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"nz.antunovic/mattr-terraform-provider/api"
+)
+
+const testCredentialId = "urn:uuid:0b6f2c1e-3a4d-4e5f-8a9b-0c1d2e3f4a5b"
+
+func TestResourceCredentialRevocationCreate(t *testing.T) {
+	client := TestClient{
+		responses: map[string]interface{}{
+			"POST https://test.api/v2/credentials/web-semantic/" + testCredentialId + "/revocation-status": nil,
+			"GET https://test.api/v2/credentials/web-semantic/" + testCredentialId + "/revocation-status": map[string]interface{}{
+				"isRevoked": true,
+			},
+		},
+	}
+
+	resource := resourceCredentialRevocation(&client)
+	resourceData := runCreate(t, resource, map[string]interface{}{
+		"credential_id": testCredentialId,
+	}, &client)
+
+	AssertEqual(t, testCredentialId, resourceData.Id(), "ID should be the credential ID")
+	AssertEqual(t, true, resourceData.Get("revoked"), "Credential should be revoked")
+	AssertEqual(t, map[string]interface{}{"isRevoked": true}, client.logs[0].body, "Request should revoke the credential")
+}
+
+func TestResourceCredentialRevocationReadsCurrentStatus(t *testing.T) {
+	client := TestClient{
+		responses: map[string]interface{}{
+			"GET https://test.api/v2/credentials/web-semantic/" + testCredentialId + "/revocation-status": map[string]interface{}{
+				"isRevoked": false,
+			},
+		},
+	}
+
+	resource := resourceCredentialRevocation(&client)
+	resourceData := resource.TestResourceData()
+	resourceData.SetId(testCredentialId)
+	resourceData.Set("credential_id", testCredentialId)
+	resourceData.Set("revoked", true)
+
+	diags := resource.ReadContext(context.Background(), resourceData, testProviderConfig())
+	if diags.HasError() {
+		t.Fatalf("Read failed: %v", diags)
+	}
+
+	// un-revoked outside Terraform, so the plan will show the drift
+	AssertEqual(t, false, resourceData.Get("revoked"), "Read should reflect the current status")
+}
+
+func TestResourceCredentialRevocationDeleteUnrevokes(t *testing.T) {
+	client := TestClient{
+		responses: map[string]interface{}{
+			"POST https://test.api/v2/credentials/web-semantic/" + testCredentialId + "/revocation-status": nil,
+		},
+	}
+
+	resource := resourceCredentialRevocation(&client)
+	resourceData := resource.TestResourceData()
+	resourceData.SetId(testCredentialId)
+	resourceData.Set("credential_id", testCredentialId)
+
+	diags := resource.DeleteContext(context.Background(), resourceData, testProviderConfig())
+	if diags.HasError() {
+		t.Fatalf("Delete failed: %v", diags)
+	}
+
+	AssertEqual(t, map[string]interface{}{"isRevoked": false}, client.logs[0].body, "Delete should un-revoke the credential")
+}
+
+func TestResourceCredentialRevocationForgetsMissingCredential(t *testing.T) {
+	client := notFoundClient{}
+
+	resource := resourceCredentialRevocation(&client)
+	resourceData := resource.TestResourceData()
+	resourceData.SetId(testCredentialId)
+	resourceData.Set("credential_id", testCredentialId)
+
+	diags := resource.ReadContext(context.Background(), resourceData, testProviderConfig())
+	if diags.HasError() {
+		t.Fatalf("Read failed: %v", diags)
+	}
+	AssertEqual(t, "", resourceData.Id(), "A missing credential should be removed from state")
+}
+
+type notFoundClient struct {
+	TestClient
+}
+
+func (client *notFoundClient) Get(ctx context.Context, url string, headers map[string]string) (*api.Response, error) {
+	return nil, api.ApiError{StatusCode: 404, Method: "GET", Url: url}
+}
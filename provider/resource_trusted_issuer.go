@@ -0,0 +1,147 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"nz.antunovic/mattr-terraform-provider/api"
+	"nz.antunovic/mattr-terraform-provider/generator"
+)
+
+const (
+	mobileTrustedIssuerPath = "/v2/credentials/mobile/trusted-issuers"
+	webTrustedIssuerPath    = "/v2/credentials/web-semantic/trusted-issuers"
+)
+
+// resourceTrustedIssuer manages a tenant-level trust anchor for verification:
+// an IACA certificate for mDocs, or a DID for web credentials
+func resourceTrustedIssuer(client api.Client) *schema.Resource {
+	trustedIssuerSchema := map[string]*schema.Schema{
+		"certificate_pem": &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     true,
+			ExactlyOneOf: []string{"certificate_pem", "did"},
+			Description:  "IACA certificate in PEM format, to trust the mDocs it issues",
+			// the same certificate can be wrapped or end its lines differently
+			DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+				return normalisePem(old) == normalisePem(new)
+			},
+		},
+		"did": &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     true,
+			ExactlyOneOf: []string{"certificate_pem", "did"},
+			Description:  "DID of an issuer of web credentials",
+		},
+		"status": &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "active",
+			ValidateFunc: oneOf(trustedIssuerStatuses),
+			Description:  "Whether presentations from this issuer are trusted: active or inactive",
+		},
+		"name": &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Name of the issuer to show to verifiers",
+		},
+		"logo_url": &schema.Schema{
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"certificate_fingerprint": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"common_name": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"country": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"not_before": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"not_after": &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+	}
+
+	generator := generator.Generator{
+		GetPath:            trustedIssuerPath,
+		Client:             client,
+		Schema:             trustedIssuerSchema,
+		Description:        fmt.Sprintf("Represents a trusted issuer at %s or %s", mobileTrustedIssuerPath, webTrustedIssuerPath),
+		ModifyResponseBody: convertTrustedIssuerRes,
+	}
+
+	resource := generator.GenResource()
+	return &resource
+}
+
+// trustedIssuerPath picks the mobile trusted issuers for certificates, and
+// the web ones for DIDs
+func trustedIssuerPath(d *schema.ResourceData) (string, error) {
+	if certificatePem, ok := d.Get("certificate_pem").(string); ok && len(certificatePem) != 0 {
+		return mobileTrustedIssuerPath, nil
+	}
+	if did, ok := d.Get("did").(string); ok && len(did) != 0 {
+		return webTrustedIssuerPath, nil
+	}
+	return "", fmt.Errorf("One of 'certificate_pem' or 'did' is required")
+}
+
+// convertTrustedIssuerRes flattens the details MATTR reads from a trusted IACA
+// certificate. Trusted DIDs have none.
+func convertTrustedIssuerRes(body interface{}) (interface{}, error) {
+	bodyMap, ok := body.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Unexpected type for trusted issuer: %T", body)
+	}
+
+	certificateData, _ := bodyMap["certificateData"].(map[string]interface{})
+	for _, field := range []string{"commonName", "country", "notBefore", "notAfter"} {
+		if value, ok := certificateData[field]; ok {
+			bodyMap[field] = value
+		}
+	}
+	delete(bodyMap, "certificateData")
+
+	return bodyMap, nil
+}
+
+// normalisePem re-encodes the PEM blocks in value, so that certificates only
+// differing in line endings, wrapping or surrounding whitespace compare equal.
+// Values that aren't PEM are compared without whitespace.
+func normalisePem(value string) string {
+	// PEM boundaries have to start their line
+	lines := strings.Split(value, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+
+	var normalised []byte
+	rest := []byte(strings.Join(lines, "\n"))
+	for {
+		block, remaining := pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		normalised = append(normalised, pem.EncodeToMemory(block)...)
+		rest = remaining
+	}
+
+	if len(normalised) == 0 || len(bytes.TrimSpace(rest)) != 0 {
+		return strings.Join(strings.Fields(value), "")
+	}
+	return string(normalised)
+}
@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestResourceTrustedIssuerCertificate(t *testing.T) {
+	client := TestClient{
+		responses: map[string]interface{}{
+			"POST https://test.api/v2/credentials/mobile/trusted-issuers": map[string]interface{}{
+				"id":                     "7f3e2d1c-0b9a-4c8d-8e7f-6a5b4c3d2e1f",
+				"certificatePem":         testIacaPem,
+				"certificateFingerprint": "3f1c7a8e",
+				"status":                 "active",
+				"name":                   "Example transport agency",
+				"certificateData": map[string]interface{}{
+					"commonName": "example.com",
+					"country":    "NZ",
+					"notBefore":  "2024-01-01T00:00:00.000Z",
+					"notAfter":   "2034-01-01T00:00:00.000Z",
+				},
+			},
+		},
+	}
+
+	resource := resourceTrustedIssuer(&client)
+	resourceData := runCreate(t, resource, map[string]interface{}{
+		"certificate_pem": testIacaPem,
+		"name":            "Example transport agency",
+	}, &client)
+
+	AssertEqual(t, "7f3e2d1c-0b9a-4c8d-8e7f-6a5b4c3d2e1f", resourceData.Id(), "ID should match")
+	AssertEqual(t, "NZ", resourceData.Get("country"), "Country should be read from the certificate")
+	AssertEqual(t, "2034-01-01T00:00:00.000Z", resourceData.Get("not_after"), "Expiry should be read from the certificate")
+}
+
+func TestResourceTrustedIssuerDid(t *testing.T) {
+	client := TestClient{
+		responses: map[string]interface{}{
+			"POST https://test.api/v2/credentials/web-semantic/trusted-issuers": map[string]interface{}{
+				"id":     "1a2b3c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d",
+				"did":    "did:web:issuer.example.com",
+				"status": "active",
+			},
+		},
+	}
+
+	resource := resourceTrustedIssuer(&client)
+	resourceData := runCreate(t, resource, map[string]interface{}{
+		"did": "did:web:issuer.example.com",
+	}, &client)
+
+	AssertEqual(t, "did:web:issuer.example.com", resourceData.Get("did"), "DID should match")
+	AssertEqual(t, "active", client.logs[0].body.(map[string]interface{})["status"], "Status should default to active")
+}
+
+func TestResourceTrustedIssuerRequiresCertificateOrDid(t *testing.T) {
+	resource := resourceTrustedIssuer(&TestClient{})
+
+	for _, config := range []map[string]interface{}{
+		{"name": "Nobody"},
+		{"certificate_pem": testIacaPem, "did": "did:web:issuer.example.com"},
+	} {
+		diags := resource.Validate(terraform.NewResourceConfigRaw(config))
+		if !diags.HasError() {
+			t.Fatalf("Expected an error for %v", config)
+		}
+	}
+}
+
+func TestResourceTrustedIssuerIgnoresPemFormatting(t *testing.T) {
+	resource := resourceTrustedIssuer(&TestClient{})
+	state := &terraform.InstanceState{
+		ID: "7f3e2d1c-0b9a-4c8d-8e7f-6a5b4c3d2e1f",
+		Attributes: map[string]string{
+			"id":              "7f3e2d1c-0b9a-4c8d-8e7f-6a5b4c3d2e1f",
+			"certificate_pem": testIacaPem,
+			"status":          "active",
+		},
+	}
+
+	reformatted := "\n  " + strings.ReplaceAll(strings.TrimSpace(testIacaPem), "\n", "\r\n") + "\r\n\n"
+	diff, err := resource.Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"certificate_pem": reformatted,
+	}), testProviderConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != nil && diff.RequiresNew() {
+		t.Fatalf("Expected reformatting the certificate not to replace the trusted issuer: %#v", diff.Attributes)
+	}
+
+	otherCertificate := "-----BEGIN CERTIFICATE-----\nMIIBOTHER\n-----END CERTIFICATE-----\n"
+	diff, err = resource.Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"certificate_pem": otherCertificate,
+	}), testProviderConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff == nil || !diff.RequiresNew() {
+		t.Fatal("Expected a different certificate to replace the trusted issuer")
+	}
+}
@@ -40,9 +40,10 @@ func resourceVerifier() *schema.Resource {
 	}
 
 	verifierGenerator := generator.Generator{
-		Path:   "/ext/oidc/v1/verifiers",
-		Client: &api.HttpClient{},
-		Schema: verifierSchema,
+		Path:                  "/ext/oidc/v1/verifiers",
+		Client:                &api.HttpClient{},
+		Schema:                verifierSchema,
+		OptimisticConcurrency: true,
 	}
 
 	provider := verifierGenerator.GenResource()
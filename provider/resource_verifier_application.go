@@ -0,0 +1,104 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"nz.antunovic/mattr-terraform-provider/api"
+	"nz.antunovic/mattr-terraform-provider/generator"
+)
+
+const verifierApplicationPath = "/v2/presentations/applications"
+
+// resourceVerifierApplication manages an application that requests mDoc
+// presentations online, as opposed to the OIDC bridge of mattr_verifier
+func resourceVerifierApplication(client api.Client) *schema.Resource {
+	verifierApplicationSchema := map[string]*schema.Schema{
+		"name": &schema.Schema{
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"type": &schema.Schema{
+			Type:         schema.TypeString,
+			Required:     true,
+			ForceNew:     true,
+			ValidateFunc: oneOf(verifierApplicationTypes),
+			Description:  "Type of application: web, ios or android",
+		},
+		"domains": &schema.Schema{
+			Type:        schema.TypeList,
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Description: "Domains the application is allowed to request presentations from. Required for web applications",
+		},
+		"redirect_uris": &schema.Schema{
+			Type:        schema.TypeList,
+			Optional:    true,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+			Description: "URIs the wallet can return the user to after a presentation",
+		},
+		"result_delivery": &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "back_channel",
+			ValidateFunc: oneOf(resultDeliveryModes),
+			Description:  "How the application receives presentation results: front_channel to the browser or app, or back_channel to its backend only",
+		},
+	}
+
+	generator := generator.Generator{
+		Path:                  verifierApplicationPath,
+		Client:                client,
+		Schema:                verifierApplicationSchema,
+		ModifyRequestBody:     convertVerifierApplicationReq,
+		ModifyResponseBody:    convertVerifierApplicationRes,
+		CustomizeDiff:         validateVerifierApplicationDomains,
+		OptimisticConcurrency: true,
+	}
+
+	resource := generator.GenResource()
+	return &resource
+}
+
+func convertVerifierApplicationReq(body interface{}) (interface{}, error) {
+	bodyMap, ok := body.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Unexpected type for %s: %T", verifierApplicationPath, body)
+	}
+
+	bodyMap["resultAvailableInFrontChannel"] = bodyMap["resultDelivery"] == "front_channel"
+	delete(bodyMap, "resultDelivery")
+
+	return bodyMap, nil
+}
+
+func convertVerifierApplicationRes(body interface{}) (interface{}, error) {
+	bodyMap, ok := body.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Unexpected type for %s: %T", verifierApplicationPath, body)
+	}
+
+	if frontChannel, _ := bodyMap["resultAvailableInFrontChannel"].(bool); frontChannel {
+		bodyMap["resultDelivery"] = "front_channel"
+	} else {
+		bodyMap["resultDelivery"] = "back_channel"
+	}
+	delete(bodyMap, "resultAvailableInFrontChannel")
+
+	return bodyMap, nil
+}
+
+// validateVerifierApplicationDomains requires web verifier applications to
+// list the domains they request presentations from
+func validateVerifierApplicationDomains(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("type") || !d.NewValueKnown("domains") {
+		return nil
+	}
+
+	if d.Get("type").(string) == "web" && len(castToStringSlice(d.Get("domains"))) == 0 {
+		return fmt.Errorf("'domains' is required when 'type' is \"web\"")
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestResourceVerifierApplicationCreate(t *testing.T) {
+	client := TestClient{
+		responses: map[string]interface{}{
+			"POST https://test.api/v2/presentations/applications": map[string]interface{}{
+				"id":                            "2c1d0e9f-8a7b-4c6d-9e5f-4a3b2c1d0e9f",
+				"name":                          "Age check",
+				"type":                          "web",
+				"domains":                       []interface{}{"shop.example.com"},
+				"redirectUris":                  []interface{}{"https://shop.example.com/verified"},
+				"resultAvailableInFrontChannel": true,
+			},
+		},
+	}
+
+	resource := resourceVerifierApplication(&client)
+	resourceData := runCreate(t, resource, map[string]interface{}{
+		"name":            "Age check",
+		"type":            "web",
+		"domains":         []interface{}{"shop.example.com"},
+		"redirect_uris":   []interface{}{"https://shop.example.com/verified"},
+		"result_delivery": "front_channel",
+	}, &client)
+
+	body := client.logs[0].body.(map[string]interface{})
+	AssertEqual(t, true, body["resultAvailableInFrontChannel"], "Front channel delivery should be requested")
+	AssertEqual(t, nil, body["resultDelivery"], "Result delivery should not be sent as is")
+	AssertEqual(t, "front_channel", resourceData.Get("result_delivery"), "Result delivery should be read back")
+}
+
+func TestResourceVerifierApplicationWebRequiresDomains(t *testing.T) {
+	resource := resourceVerifierApplication(&TestClient{})
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name": "Age check",
+		"type": "web",
+	})
+
+	if _, err := resource.Diff(context.Background(), nil, config, nil); err == nil {
+		t.Fatal("Expected an error for a web application without domains")
+	}
+}
@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 	"nz.antunovic/mattr-terraform-provider/api"
 	"nz.antunovic/mattr-terraform-provider/generator"
@@ -27,24 +28,33 @@ func resourceVerifierClient(client api.Client) *schema.Resource {
 		"response_types": &schema.Schema{
 			Type:     schema.TypeList,
 			Optional: true,
-			Elem:     &schema.Schema{Type: schema.TypeString},
+			Elem: &schema.Schema{
+				Type:         schema.TypeString,
+				ValidateFunc: oneOf(responseTypes),
+			},
 		},
 		"grant_types": &schema.Schema{
 			Type:     schema.TypeList,
 			Optional: true,
-			Elem:     &schema.Schema{Type: schema.TypeString},
+			Elem: &schema.Schema{
+				Type:         schema.TypeString,
+				ValidateFunc: oneOf(grantTypes),
+			},
 		},
 		"token_endpoint_auth_method": &schema.Schema{
-			Type:     schema.TypeString,
-			Optional: true,
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: oneOf(tokenEndpointAuthMethods),
 		},
 		"id_token_signed_response_alg": &schema.Schema{
-			Type:     schema.TypeString,
-			Required: true,
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: oneOf(idTokenSignedResponseAlgs),
 		},
 		"application_type": &schema.Schema{
-			Type:     schema.TypeString,
-			Optional: true,
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: oneOf(applicationTypes),
 		},
 		"logo_uri": &schema.Schema{
 			Type:     schema.TypeString,
@@ -55,12 +65,14 @@ func resourceVerifierClient(client api.Client) *schema.Resource {
 			Computed: true,
 		},
 		"openid_configuration_url": &schema.Schema{
-			Type:     schema.TypeString,
-			Computed: true,
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "URL of the verifier's OpenID Provider metadata",
 		},
 		"authorization_url": &schema.Schema{
-			Type:     schema.TypeString,
-			Computed: true,
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "URL of the verifier's authorization endpoint, which the client sends users to",
 		},
 	}
 
@@ -86,8 +98,35 @@ func resourceVerifierClient(client api.Client) *schema.Resource {
 		Client:            client,
 		Schema:            verifierClientSchema,
 		ModifyRequestBody: verifierClientModifyRes,
+		CustomizeDiff:     validateClientGrantTypes,
+		AfterOperation:    setVerifierClientUrls,
 	}
 	resource := generator.GenResource()
 
 	return &resource
 }
+
+func setVerifierClientUrls(ctx context.Context, d *schema.ResourceData, m interface{}, response *api.Response) error {
+	verifierId := d.Get("verifier_id").(string)
+
+	openidConfigurationUrl, err := verifierOidcUrl(m, verifierId, "/.well-known/openid-configuration")
+	if err != nil {
+		return err
+	}
+	if err := d.Set("openid_configuration_url", openidConfigurationUrl); err != nil {
+		return err
+	}
+
+	authorizationUrl, err := verifierOidcUrl(m, verifierId, "/authorize")
+	if err != nil {
+		return err
+	}
+	return d.Set("authorization_url", authorizationUrl)
+}
+
+// verifierOidcUrl returns the URL of an endpoint of a verifier's OpenID
+// Provider, e.g. /authorize
+func verifierOidcUrl(m interface{}, verifierId string, endpoint string) (string, error) {
+	providerApi := m.(api.ProviderConfig).Api
+	return providerApi.GetUrl(fmt.Sprintf("/ext/oidc/v1/verifiers/%s%s", verifierId, endpoint))
+}
@@ -62,4 +62,6 @@ func TestResourceVerifierClientCreate(t *testing.T) {
 	AssertEqual(t, "ES256", resourceData.Get("id_token_signed_response_alg"), "Algorithm should be correct")
 	AssertEqual(t, "web", resourceData.Get("application_type"), "Application type should be correct")
 	AssertEqual(t, "https://example.com/logo.png", resourceData.Get("logo_uri"), "Logo should be correct")
+	AssertEqual(t, "https://test.api/ext/oidc/v1/verifiers/402c65eb-48e9-4a4c-b5e9-1ea615baccee/.well-known/openid-configuration", resourceData.Get("openid_configuration_url"), "OpenID configuration URL should be correct")
+	AssertEqual(t, "https://test.api/ext/oidc/v1/verifiers/402c65eb-48e9-4a4c-b5e9-1ea615baccee/authorize", resourceData.Get("authorization_url"), "Authorization URL should be correct")
 }
@@ -9,13 +9,15 @@ import (
 
 func resourceWebhook(client api.Client) *schema.Resource {
 	generator := generator.Generator{
-		Path:   "/core/v1/webhooks",
-		Client: client,
+		Path:                  "/core/v1/webhooks",
+		Client:                client,
+		OptimisticConcurrency: true,
 		Schema: map[string]*schema.Schema{
 			"events": &schema.Schema{
 				Type: schema.TypeList,
 				Elem: &schema.Schema{
-					Type: schema.TypeString,
+					Type:         schema.TypeString,
+					ValidateFunc: oneOf(webhookEvents),
 				},
 				Description: "Types of events we will look out for and send to the webhook",
 				Required:    true,
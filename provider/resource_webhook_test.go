@@ -57,3 +57,88 @@ func TestResourceWebhookCreateExtraneousFields(t *testing.T) {
 	AssertEqual(t, createData["url"], resourceData.Get("url"), "URL should match")
 	AssertEqual(t, createData["disabled"], resourceData.Get("disabled"), "Disabled should match")
 }
+
+func TestResourceWebhookUpdate(t *testing.T) {
+	client := TestClient{
+		responses: map[string]interface{}{
+			"POST https://test.api/core/v1/webhooks": map[string]interface{}{
+				"id":       "8e485582-6ef6-49bc-80fa-25a1b36a8322",
+				"events":   []interface{}{"OidcIssuerCredentialIssued"},
+				"url":      "https://test.api/webhook",
+				"disabled": false,
+			},
+			"PUT https://test.api/core/v1/webhooks/8e485582-6ef6-49bc-80fa-25a1b36a8322": map[string]interface{}{
+				"id":       "8e485582-6ef6-49bc-80fa-25a1b36a8322",
+				"events":   []interface{}{"OidcIssuerCredentialIssued", "PresentationSubmitted"},
+				"url":      "https://test.api/webhook",
+				"disabled": false,
+			},
+		},
+	}
+	createData := map[string]interface{}{
+		"events":   []interface{}{"OidcIssuerCredentialIssued"},
+		"url":      "https://test.api/webhook",
+		"disabled": false,
+	}
+	updateData := map[string]interface{}{
+		"events":   []interface{}{"OidcIssuerCredentialIssued", "PresentationSubmitted"},
+		"url":      "https://test.api/webhook",
+		"disabled": false,
+	}
+
+	resource := resourceWebhook(&client)
+	state := runUpdate(t, resource, createData, updateData, &client)
+
+	request := client.logs[len(client.logs)-1]
+	AssertEqual(t, "PUT", request.method, "Update should use PUT")
+	AssertEqual(t, map[string]interface{}{
+		"events":   []interface{}{"OidcIssuerCredentialIssued", "PresentationSubmitted"},
+		"url":      "https://test.api/webhook",
+		"disabled": false,
+	}, request.body, "The whole webhook should be sent")
+	AssertEqual(t, "2", state.Attributes["events.#"], "Events should be updated")
+}
+
+func TestResourceWebhookUpdateSendsIfMatch(t *testing.T) {
+	client := TestClient{
+		responses: map[string]interface{}{
+			"POST https://test.api/core/v1/webhooks": map[string]interface{}{
+				"id":       "8e485582-6ef6-49bc-80fa-25a1b36a8322",
+				"events":   []interface{}{"OidcIssuerCredentialIssued"},
+				"url":      "https://test.api/webhook",
+				"disabled": false,
+			},
+			"PUT https://test.api/core/v1/webhooks/8e485582-6ef6-49bc-80fa-25a1b36a8322": map[string]interface{}{
+				"id":       "8e485582-6ef6-49bc-80fa-25a1b36a8322",
+				"events":   []interface{}{"OidcIssuerCredentialIssued"},
+				"url":      "https://test.api/webhook",
+				"disabled": true,
+			},
+		},
+		headers: map[string]map[string]string{
+			"POST https://test.api/core/v1/webhooks": {
+				"Etag": `"1"`,
+			},
+			"PUT https://test.api/core/v1/webhooks/8e485582-6ef6-49bc-80fa-25a1b36a8322": {
+				"Etag": `"2"`,
+			},
+		},
+	}
+	createData := map[string]interface{}{
+		"events":   []interface{}{"OidcIssuerCredentialIssued"},
+		"url":      "https://test.api/webhook",
+		"disabled": false,
+	}
+	updateData := map[string]interface{}{
+		"events":   []interface{}{"OidcIssuerCredentialIssued"},
+		"url":      "https://test.api/webhook",
+		"disabled": true,
+	}
+
+	resource := resourceWebhook(&client)
+	state := runUpdate(t, resource, createData, updateData, &client)
+
+	request := client.logs[len(client.logs)-1]
+	AssertEqual(t, `"1"`, request.headers["If-Match"], "Update should send the ETag from create")
+	AssertEqual(t, `"2"`, state.Attributes["etag"], "ETag should be updated from the response")
+}
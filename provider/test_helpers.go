@@ -1,7 +1,9 @@
 package provider
 
 import (
+	"context"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"math"
 	"nz.antunovic/mattr-terraform-provider/api"
 	"reflect"
@@ -14,8 +16,8 @@ func AssertEqual(t *testing.T, expected, actual interface{}, msg string) {
 	}
 }
 
-func runCreate(t *testing.T, resource *schema.Resource, createData map[string]interface{}, client api.Client) *schema.ResourceData {
-	provider_config := api.ProviderConfig{
+func testProviderConfig() api.ProviderConfig {
+	return api.ProviderConfig{
 		Api: api.Api{
 			ClientId:             "test-id",
 			ClientSecret:         "test-scret",
@@ -26,19 +28,25 @@ func runCreate(t *testing.T, resource *schema.Resource, createData map[string]in
 			AccessTokenExpiresAt: math.MaxInt,
 		},
 	}
+}
+
+func runCreate(t *testing.T, resource *schema.Resource, createData map[string]interface{}, client api.Client) *schema.ResourceData {
+	provider_config := testProviderConfig()
 
 	createCtx := schema.TestResourceDataRaw(t, resource.Schema, createData)
+	// as Terraform does before creating a resource
+	createCtx.MarkNewResource()
 
 	// Set the createData in the resource context
 	for k, v := range createData {
 		createCtx.Set(k, v)
 	}
 
-	err := resource.Create(createCtx, provider_config)
+	diags := resource.CreateContext(context.Background(), createCtx, provider_config)
 
 	// Assert that Create succeeded without errors
-	if err != nil {
-		t.Fatalf("Create failed: %v", err)
+	if diags.HasError() {
+		t.Fatalf("Create failed: %v", diags)
 	}
 
 	// Assert that the resource has an ID after creation
@@ -49,3 +57,36 @@ func runCreate(t *testing.T, resource *schema.Resource, createData map[string]in
 	return createCtx
 
 }
+
+func runUpdate(t *testing.T, resource *schema.Resource, createData map[string]interface{}, updateData map[string]interface{}, client api.Client) *terraform.InstanceState {
+	provider_config := testProviderConfig()
+	state := runCreate(t, resource, createData, client).State()
+
+	config := terraform.NewResourceConfigRaw(updateData)
+	diff, err := resource.Diff(context.Background(), state, config, provider_config)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+
+	newState, diags := resource.Apply(context.Background(), state, diff, provider_config)
+
+	// Assert that Update succeeded without errors
+	if diags.HasError() {
+		t.Fatalf("Update failed: %v", diags)
+	}
+
+	return newState
+}
+
+func runRead(t *testing.T, dataSource *schema.Resource, readData map[string]interface{}, client api.Client) *schema.ResourceData {
+	readCtx := schema.TestResourceDataRaw(t, dataSource.Schema, readData)
+
+	diags := dataSource.ReadContext(context.Background(), readCtx, testProviderConfig())
+
+	// Assert that Read succeeded without errors
+	if diags.HasError() {
+		t.Fatalf("Read failed: %v", diags)
+	}
+
+	return readCtx
+}
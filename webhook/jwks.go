@@ -0,0 +1,92 @@
+// Package webhook verifies the HTTP message signatures on webhook requests
+// sent by MATTR, using the tenant's webhook JWKS from
+// /core/v1/webhooks/jwks (also available as the `mattr_webhook_jwks` data
+// source).
+package webhook
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// JWK is a public key in JSON Web Key format. Only the members needed to
+// verify signatures are kept.
+type JWK struct {
+	Kid string `json:"kid,omitempty"`
+	Kty string `json:"kty"`
+	Crv string `json:"crv,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// ParseJWKS parses a JWKS such as the response from /core/v1/webhooks/jwks
+func ParseJWKS(data []byte) (*JWKS, error) {
+	var jwks JWKS
+	if err := json.Unmarshal(data, &jwks); err != nil {
+		return nil, fmt.Errorf("Unable to parse JWKS: %s", err)
+	}
+	return &jwks, nil
+}
+
+// Key finds the key with the given ID. If the JWKS holds a single key and
+// no ID is given, that key is returned.
+func (jwks *JWKS) Key(kid string) (*JWK, error) {
+	if len(kid) == 0 {
+		if len(jwks.Keys) == 1 {
+			return &jwks.Keys[0], nil
+		}
+		return nil, fmt.Errorf("The signature has no keyid and the JWKS has %d keys", len(jwks.Keys))
+	}
+
+	for i := range jwks.Keys {
+		if jwks.Keys[i].Kid == kid {
+			return &jwks.Keys[i], nil
+		}
+	}
+	return nil, fmt.Errorf("No key with ID %q in the JWKS", kid)
+}
+
+// PublicKey decodes the JWK into an ed25519.PublicKey or *ecdsa.PublicKey
+func (jwk *JWK) PublicKey() (interface{}, error) {
+	switch {
+	case jwk.Kty == "OKP" && jwk.Crv == "Ed25519":
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to decode 'x' of key %q: %s", jwk.Kid, err)
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("Key %q is %d bytes, not %d", jwk.Kid, len(x), ed25519.PublicKeySize)
+		}
+		return ed25519.PublicKey(x), nil
+	case jwk.Kty == "EC" && jwk.Crv == "P-256":
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to decode 'x' of key %q: %s", jwk.Kid, err)
+		}
+		y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to decode 'y' of key %q: %s", jwk.Kid, err)
+		}
+		key := &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+		if !key.Curve.IsOnCurve(key.X, key.Y) {
+			return nil, fmt.Errorf("Key %q is not on the P-256 curve", jwk.Kid)
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("Key %q has unsupported type %q and curve %q", jwk.Kid, jwk.Kty, jwk.Crv)
+	}
+}
@@ -0,0 +1,385 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultMaxAge is how old a signature can be when Verifier.MaxAge isn't set
+const DefaultMaxAge = 5 * time.Minute
+
+// DefaultClockSkew is how far ahead of our clock a signature's 'created' time
+// can be when Verifier.ClockSkew isn't set
+const DefaultClockSkew = time.Minute
+
+// Verifier checks the HTTP message signature (RFC 9421) on a webhook request
+// against a JWKS. The request body is checked against its Content-Digest
+// header, which must be covered by the signature.
+type Verifier struct {
+	Keys *JWKS
+	// MaxAge rejects signatures created longer ago than this, to limit
+	// replays, and defaults to DefaultMaxAge. Signatures must have a 'created'
+	// parameter. A negative MaxAge accepts signatures of any age, so a
+	// captured request can be replayed.
+	MaxAge time.Duration
+	// ClockSkew allows for the signer's clock running ahead of ours, and
+	// defaults to DefaultClockSkew. Signatures created further in the future
+	// are rejected, as they would otherwise outlive MaxAge.
+	ClockSkew time.Duration
+	// Now is used to check MaxAge and expiry, and defaults to time.Now
+	Now func() time.Time
+}
+
+// Verify checks the request's signature and returns the body that was
+// signed. The body is also restored on the request so it can be read again.
+func (v Verifier) Verify(r *http.Request) ([]byte, error) {
+	if v.Keys == nil {
+		return nil, fmt.Errorf("No JWKS to verify the signature with")
+	}
+
+	var body []byte
+	if r.Body != nil {
+		var err error
+		body, err = io.ReadAll(r.Body)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to read request body: %s", err)
+		}
+		r.Body.Close()
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	signatureInputs, err := parseDictionary(r.Header.Get("Signature-Input"))
+	if err != nil {
+		return nil, fmt.Errorf("Invalid Signature-Input header: %s", err)
+	}
+	signatures, err := parseDictionary(r.Header.Get("Signature"))
+	if err != nil {
+		return nil, fmt.Errorf("Invalid Signature header: %s", err)
+	}
+	if len(signatureInputs) == 0 {
+		return nil, fmt.Errorf("The request is not signed")
+	}
+
+	// every signature on the request must be valid
+	for _, input := range signatureInputs {
+		signature, ok := signatures.get(input.label)
+		if !ok {
+			return nil, fmt.Errorf("No signature for label %q", input.label)
+		}
+		if err := v.verifySignature(r, body, input, signature); err != nil {
+			return nil, fmt.Errorf("Signature %q is invalid: %s", input.label, err)
+		}
+	}
+
+	return body, nil
+}
+
+func (v Verifier) verifySignature(r *http.Request, body []byte, input member, signature member) error {
+	components, params, err := parseSignatureParams(input.value)
+	if err != nil {
+		return err
+	}
+
+	if len(body) != 0 && !containsComponent(components, "content-digest") {
+		return fmt.Errorf("The signature doesn't cover content-digest, so the body can't be trusted")
+	}
+	if containsComponent(components, "content-digest") {
+		if err := verifyContentDigest(r.Header.Get("Content-Digest"), body); err != nil {
+			return err
+		}
+	}
+
+	created, err := strconv.ParseInt(params["created"], 10, 64)
+	if err != nil {
+		return fmt.Errorf("The signature has no valid 'created' parameter")
+	}
+	maxAge := v.MaxAge
+	if maxAge == 0 {
+		maxAge = DefaultMaxAge
+	}
+	clockSkew := v.ClockSkew
+	if clockSkew == 0 {
+		clockSkew = DefaultClockSkew
+	}
+	age := v.now().Sub(time.Unix(created, 0))
+	if maxAge > 0 && age > maxAge {
+		return fmt.Errorf("The signature was created %s ago, which is more than %s", age.Round(time.Second), maxAge)
+	}
+	if -age > clockSkew {
+		return fmt.Errorf("The signature was created %s in the future, which is more than the allowed clock skew of %s", (-age).Round(time.Second), clockSkew)
+	}
+	if expires, ok := params["expires"]; ok {
+		expiresAt, err := strconv.ParseInt(expires, 10, 64)
+		if err != nil {
+			return fmt.Errorf("Invalid 'expires' parameter: %s", expires)
+		}
+		if v.now().After(time.Unix(expiresAt, 0)) {
+			return fmt.Errorf("The signature expired at %s", time.Unix(expiresAt, 0).UTC())
+		}
+	}
+
+	jwk, err := v.Keys.Key(params["keyid"])
+	if err != nil {
+		return err
+	}
+	key, err := jwk.PublicKey()
+	if err != nil {
+		return err
+	}
+
+	base, err := signatureBase(r, components, input.value)
+	if err != nil {
+		return err
+	}
+
+	sig, err := decodeByteSequence(signature.value)
+	if err != nil {
+		return err
+	}
+
+	return verifyWithKey(key, params["alg"], []byte(base), sig)
+}
+
+func (v Verifier) now() time.Time {
+	if v.Now != nil {
+		return v.Now()
+	}
+	return time.Now()
+}
+
+func verifyWithKey(key interface{}, alg string, base []byte, sig []byte) error {
+	switch key := key.(type) {
+	case ed25519.PublicKey:
+		if len(alg) != 0 && alg != "ed25519" {
+			return fmt.Errorf("Algorithm %q can't be used with an Ed25519 key", alg)
+		}
+		if !ed25519.Verify(key, base, sig) {
+			return fmt.Errorf("Ed25519 signature verification failed")
+		}
+		return nil
+	case *ecdsa.PublicKey:
+		if len(alg) != 0 && alg != "ecdsa-p256-sha256" {
+			return fmt.Errorf("Algorithm %q can't be used with a P-256 key", alg)
+		}
+		// the signature is r || s, as in JWS
+		if len(sig) != 64 {
+			return fmt.Errorf("ECDSA signature is %d bytes, not 64", len(sig))
+		}
+		digest := sha256.Sum256(base)
+		r := new(big.Int).SetBytes(sig[:32])
+		s := new(big.Int).SetBytes(sig[32:])
+		if !ecdsa.Verify(key, digest[:], r, s) {
+			return fmt.Errorf("ECDSA signature verification failed")
+		}
+		return nil
+	default:
+		return fmt.Errorf("Unsupported key type %T", key)
+	}
+}
+
+// signatureBase builds the string that was signed, from the covered
+// components and the raw signature parameters
+func signatureBase(r *http.Request, components []string, signatureParams string) (string, error) {
+	var sb strings.Builder
+	for _, component := range components {
+		value, err := componentValue(r, component)
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(fmt.Sprintf("%q: %s\n", component, value))
+	}
+	sb.WriteString(fmt.Sprintf("%q: %s", "@signature-params", signatureParams))
+	return sb.String(), nil
+}
+
+func componentValue(r *http.Request, component string) (string, error) {
+	switch component {
+	case "@method":
+		return r.Method, nil
+	case "@authority":
+		return strings.ToLower(r.Host), nil
+	case "@path":
+		return r.URL.EscapedPath(), nil
+	case "@query":
+		return "?" + r.URL.RawQuery, nil
+	case "@request-target":
+		return r.URL.RequestURI(), nil
+	}
+
+	if strings.HasPrefix(component, "@") {
+		return "", fmt.Errorf("Unsupported derived component %q", component)
+	}
+
+	// Values returns the request's own slice, so trim into a copy
+	headerValues := r.Header.Values(component)
+	if len(headerValues) == 0 {
+		return "", fmt.Errorf("The signature covers %q, which is not in the request", component)
+	}
+	values := make([]string, len(headerValues))
+	for i, value := range headerValues {
+		values[i] = strings.TrimSpace(value)
+	}
+	return strings.Join(values, ", "), nil
+}
+
+func verifyContentDigest(header string, body []byte) error {
+	digests, err := parseDictionary(header)
+	if err != nil {
+		return fmt.Errorf("Invalid Content-Digest header: %s", err)
+	}
+
+	for _, digest := range digests {
+		var expected []byte
+		switch digest.label {
+		case "sha-256":
+			sum := sha256.Sum256(body)
+			expected = sum[:]
+		case "sha-512":
+			sum := sha512.Sum512(body)
+			expected = sum[:]
+		default:
+			continue
+		}
+
+		actual, err := decodeByteSequence(digest.value)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(expected, actual) {
+			return fmt.Errorf("The body doesn't match its %s Content-Digest", digest.label)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("The request has no sha-256 or sha-512 Content-Digest")
+}
+
+func containsComponent(components []string, component string) bool {
+	for _, c := range components {
+		if c == component {
+			return true
+		}
+	}
+	return false
+}
+
+// member is an entry in a structured field dictionary, with its value kept
+// as raw text because the signature base needs it verbatim
+type member struct {
+	label string
+	value string
+}
+
+type dictionary []member
+
+func (members dictionary) get(label string) (member, bool) {
+	for _, m := range members {
+		if m.label == label {
+			return m, true
+		}
+	}
+	return member{}, false
+}
+
+// parseDictionary splits a structured field dictionary (RFC 8941) into its
+// members. Only as much of the syntax as signatures use is understood.
+func parseDictionary(header string) (dictionary, error) {
+	members := dictionary{}
+	for _, entry := range splitOutside(header, ',') {
+		entry = strings.TrimSpace(entry)
+		if len(entry) == 0 {
+			continue
+		}
+		eq := strings.Index(entry, "=")
+		if eq <= 0 {
+			return nil, fmt.Errorf("Expected label=value, got %q", entry)
+		}
+		members = append(members, member{
+			label: strings.TrimSpace(entry[:eq]),
+			value: strings.TrimSpace(entry[eq+1:]),
+		})
+	}
+	return members, nil
+}
+
+// parseSignatureParams parses an inner list such as
+// ("@method" "content-digest");created=1618884473;keyid="test-key"
+func parseSignatureParams(value string) ([]string, map[string]string, error) {
+	if !strings.HasPrefix(value, "(") {
+		return nil, nil, fmt.Errorf("Expected a list of components, got %q", value)
+	}
+	end := strings.Index(value, ")")
+	if end < 0 {
+		return nil, nil, fmt.Errorf("Unterminated list of components in %q", value)
+	}
+
+	components := []string{}
+	for _, item := range strings.Fields(value[1:end]) {
+		component, err := strconv.Unquote(item)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Invalid component %s", item)
+		}
+		components = append(components, strings.ToLower(component))
+	}
+
+	params := map[string]string{}
+	for _, param := range splitOutside(value[end+1:], ';') {
+		param = strings.TrimSpace(param)
+		if len(param) == 0 {
+			continue
+		}
+		name, paramValue, _ := strings.Cut(param, "=")
+		if unquoted, err := strconv.Unquote(paramValue); err == nil {
+			paramValue = unquoted
+		}
+		params[name] = paramValue
+	}
+
+	return components, params, nil
+}
+
+// decodeByteSequence decodes a structured field byte sequence, :base64:
+func decodeByteSequence(value string) ([]byte, error) {
+	if len(value) < 2 || !strings.HasPrefix(value, ":") || !strings.HasSuffix(value, ":") {
+		return nil, fmt.Errorf("Expected a byte sequence, got %q", value)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(value[1 : len(value)-1])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid base64 in %q: %s", value, err)
+	}
+	return decoded, nil
+}
+
+// splitOutside splits on sep when it's not inside quotes or parentheses
+func splitOutside(s string, sep rune) []string {
+	parts := []string{}
+	depth := 0
+	quoted := false
+	start := 0
+	for i, c := range s {
+		switch {
+		case c == '"' && (i == 0 || s[i-1] != '\\'):
+			quoted = !quoted
+		case quoted:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == sep && depth == 0:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
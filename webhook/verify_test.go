@@ -0,0 +1,312 @@
+package webhook
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testBody = `{"event":{"type":"OidcIssuerCredentialIssued"}}`
+
+func newSignedRequest(t *testing.T, body string, keyid string, alg string, sign func([]byte) []byte) *http.Request {
+	r := httptest.NewRequest("POST", "https://hooks.example.com/mattr?tenant=1", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+
+	digest := sha256.Sum256([]byte(body))
+	r.Header.Set("Content-Digest", fmt.Sprintf("sha-256=:%s:", base64.StdEncoding.EncodeToString(digest[:])))
+
+	params := fmt.Sprintf(`("@method" "@path" "@authority" "content-type" "content-digest");created=%d;keyid="%s";alg="%s"`, time.Now().Unix(), keyid, alg)
+	base, err := signatureBase(r, []string{"@method", "@path", "@authority", "content-type", "content-digest"}, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.Header.Set("Signature-Input", "sig1="+params)
+	r.Header.Set("Signature", fmt.Sprintf("sig1=:%s:", base64.StdEncoding.EncodeToString(sign([]byte(base)))))
+	return r
+}
+
+func ed25519Jwks(t *testing.T) (*JWKS, ed25519.PrivateKey) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwks := &JWKS{Keys: []JWK{{
+		Kid: "ed-key",
+		Kty: "OKP",
+		Crv: "Ed25519",
+		X:   base64.RawURLEncoding.EncodeToString(public),
+	}}}
+	return jwks, private
+}
+
+func signEd25519(private ed25519.PrivateKey) func([]byte) []byte {
+	return func(base []byte) []byte {
+		return ed25519.Sign(private, base)
+	}
+}
+
+func TestVerifyEd25519(t *testing.T) {
+	jwks, private := ed25519Jwks(t)
+	r := newSignedRequest(t, testBody, "ed-key", "ed25519", signEd25519(private))
+
+	body, err := Verifier{Keys: jwks, MaxAge: time.Minute}.Verify(r)
+	if err != nil {
+		t.Fatalf("Expected the signature to be valid: %s", err)
+	}
+	if string(body) != testBody {
+		t.Fatalf("Unexpected body %s", body)
+	}
+
+	// the body can still be read by the handler
+	restored, _ := io.ReadAll(r.Body)
+	if string(restored) != testBody {
+		t.Fatalf("Body was not restored, got %s", restored)
+	}
+}
+
+func TestVerifyES256(t *testing.T) {
+	private, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwks := &JWKS{Keys: []JWK{{
+		Kid: "ec-key",
+		Kty: "EC",
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(private.X.FillBytes(make([]byte, 32))),
+		Y:   base64.RawURLEncoding.EncodeToString(private.Y.FillBytes(make([]byte, 32))),
+	}}}
+
+	r := newSignedRequest(t, testBody, "ec-key", "ecdsa-p256-sha256", func(base []byte) []byte {
+		digest := sha256.Sum256(base)
+		sigR, sigS, err := ecdsa.Sign(rand.Reader, private, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return append(sigR.FillBytes(make([]byte, 32)), sigS.FillBytes(make([]byte, 32))...)
+	})
+
+	if _, err := (Verifier{Keys: jwks}).Verify(r); err != nil {
+		t.Fatalf("Expected the signature to be valid: %s", err)
+	}
+}
+
+func TestVerifyRejectsTamperedBody(t *testing.T) {
+	jwks, private := ed25519Jwks(t)
+	r := newSignedRequest(t, testBody, "ed-key", "ed25519", signEd25519(private))
+	r.Body = io.NopCloser(strings.NewReader(`{"event":{"type":"PresentationSubmitted"}}`))
+
+	if _, err := (Verifier{Keys: jwks}).Verify(r); err == nil {
+		t.Fatal("Expected a tampered body to be rejected")
+	}
+}
+
+func TestVerifyRejectsTamperedHeader(t *testing.T) {
+	jwks, private := ed25519Jwks(t)
+	r := newSignedRequest(t, testBody, "ed-key", "ed25519", signEd25519(private))
+	r.Header.Set("Content-Type", "text/plain")
+
+	if _, err := (Verifier{Keys: jwks}).Verify(r); err == nil {
+		t.Fatal("Expected a tampered header to be rejected")
+	}
+}
+
+func TestVerifyRejectsOtherKey(t *testing.T) {
+	jwks, _ := ed25519Jwks(t)
+	_, otherPrivate := ed25519Jwks(t)
+	r := newSignedRequest(t, testBody, "ed-key", "ed25519", signEd25519(otherPrivate))
+
+	if _, err := (Verifier{Keys: jwks}).Verify(r); err == nil {
+		t.Fatal("Expected a signature from another key to be rejected")
+	}
+}
+
+func TestVerifyRejectsUnknownKeyId(t *testing.T) {
+	jwks, private := ed25519Jwks(t)
+	r := newSignedRequest(t, testBody, "rotated-key", "ed25519", signEd25519(private))
+
+	if _, err := (Verifier{Keys: jwks}).Verify(r); err == nil {
+		t.Fatal("Expected an unknown keyid to be rejected")
+	}
+}
+
+func TestVerifyRejectsOldSignature(t *testing.T) {
+	jwks, private := ed25519Jwks(t)
+	r := newSignedRequest(t, testBody, "ed-key", "ed25519", signEd25519(private))
+
+	verifier := Verifier{
+		Keys:   jwks,
+		MaxAge: 5 * time.Minute,
+		Now:    func() time.Time { return time.Now().Add(time.Hour) },
+	}
+	if _, err := verifier.Verify(r); err == nil {
+		t.Fatal("Expected an old signature to be rejected")
+	}
+}
+
+func TestVerifyRejectsFutureSignature(t *testing.T) {
+	jwks, private := ed25519Jwks(t)
+	r := newSignedRequest(t, testBody, "ed-key", "ed25519", signEd25519(private))
+
+	verifier := Verifier{
+		Keys: jwks,
+		Now:  func() time.Time { return time.Now().Add(-DefaultClockSkew - time.Minute) },
+	}
+	if _, err := verifier.Verify(r); err == nil {
+		t.Fatal("Expected a signature created in the future to be rejected")
+	}
+
+	verifier.Now = func() time.Time { return time.Now().Add(-DefaultClockSkew / 2) }
+	if _, err := verifier.Verify(r); err != nil {
+		t.Fatalf("Expected a signature within the clock skew to be accepted: %s", err)
+	}
+}
+
+func TestVerifyRejectsSignatureWithoutCreated(t *testing.T) {
+	jwks, private := ed25519Jwks(t)
+	r := newSignedRequest(t, testBody, "ed-key", "ed25519", signEd25519(private))
+
+	// re-sign without the created parameter, which would allow replays forever
+	params := `("@method" "@path" "@authority" "content-type" "content-digest");keyid="ed-key";alg="ed25519"`
+	base, err := signatureBase(r, []string{"@method", "@path", "@authority", "content-type", "content-digest"}, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Header.Set("Signature-Input", "sig1="+params)
+	r.Header.Set("Signature", fmt.Sprintf("sig1=:%s:", base64.StdEncoding.EncodeToString(ed25519.Sign(private, []byte(base)))))
+
+	if _, err := (Verifier{Keys: jwks}).Verify(r); err == nil {
+		t.Fatal("Expected a signature without 'created' to be rejected")
+	}
+}
+
+func TestVerifyDefaultMaxAge(t *testing.T) {
+	jwks, private := ed25519Jwks(t)
+	r := newSignedRequest(t, testBody, "ed-key", "ed25519", signEd25519(private))
+
+	verifier := Verifier{
+		Keys: jwks,
+		Now:  func() time.Time { return time.Now().Add(DefaultMaxAge + time.Minute) },
+	}
+	if _, err := verifier.Verify(r); err == nil {
+		t.Fatal("Expected a signature older than the default max age to be rejected")
+	}
+
+	verifier.MaxAge = -1
+	if _, err := verifier.Verify(r); err != nil {
+		t.Fatalf("Expected a negative max age to accept old signatures: %s", err)
+	}
+}
+
+func TestVerifyLeavesHeadersUnchanged(t *testing.T) {
+	jwks, private := ed25519Jwks(t)
+	r := newSignedRequest(t, testBody, "ed-key", "ed25519", signEd25519(private))
+	r.Header["Content-Type"] = []string{" application/json "}
+
+	// the signature no longer matches, but the header must be left as it was
+	(Verifier{Keys: jwks}).Verify(r)
+	if r.Header.Get("Content-Type") != " application/json " {
+		t.Fatalf("Verification changed the Content-Type header to %q", r.Header.Get("Content-Type"))
+	}
+}
+
+// TestVerifyRfc9421Example checks the signature base and Ed25519 verification
+// against the example in RFC 9421 Appendix B.2.6, which was signed with
+// test-key-ed25519 from Appendix B.1.4
+func TestVerifyRfc9421Example(t *testing.T) {
+	r := httptest.NewRequest("POST", "http://example.com/foo?param=Value&Pet=dog", strings.NewReader(`{"hello": "world"}`))
+	r.Header.Set("Date", "Tue, 20 Apr 2021 02:07:55 GMT")
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Content-Digest", "sha-512=:WZDPaVn/7XgHaAy8pmojAkGWoRx2UFChF41A2svX+TaPm+AbwAgBWnrIiYllu7BNNyealdVLvRwEmTHWXvJwew==:")
+	r.Header.Set("Content-Length", "18")
+	r.Header.Set("Signature-Input", `sig-b26=("date" "@method" "@path" "@authority" "content-type" "content-length");created=1618884473;keyid="test-key-ed25519"`)
+	r.Header.Set("Signature", "sig-b26=:wqcAqbmYJ2ji2glfAMaRy4gruYYnx2nEFN2HN6jrnDnQCK1u02Gb04v9EDgwUPiu4A0w6vuQv5lIp5WPpBKRCw==:")
+
+	jwks := &JWKS{Keys: []JWK{{
+		Kid: "test-key-ed25519",
+		Kty: "OKP",
+		Crv: "Ed25519",
+		X:   "JrQLj5P_89iXES9-vFgrIy29clF9CC_oPPsw3c5D0bs",
+	}}}
+
+	input, _ := parseDictionary(r.Header.Get("Signature-Input"))
+	components, _, err := parseSignatureParams(input[0].value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	base, err := signatureBase(r, components, input[0].value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `"date": Tue, 20 Apr 2021 02:07:55 GMT
+"@method": POST
+"@path": /foo
+"@authority": example.com
+"content-type": application/json
+"content-length": 18
+"@signature-params": ("date" "@method" "@path" "@authority" "content-type" "content-length");created=1618884473;keyid="test-key-ed25519"`
+	if base != expected {
+		t.Fatalf("Signature base doesn't match the RFC.\nExpected:\n%s\nGot:\n%s", expected, base)
+	}
+
+	key, err := jwks.Keys[0].PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signatures, _ := parseDictionary(r.Header.Get("Signature"))
+	sig, err := decodeByteSequence(signatures[0].value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := verifyWithKey(key, "", []byte(base), sig); err != nil {
+		t.Fatalf("Expected the RFC example signature to be valid: %s", err)
+	}
+
+	// the example doesn't cover content-digest, so its body can't be trusted
+	verifier := Verifier{
+		Keys: jwks,
+		Now:  func() time.Time { return time.Unix(1618884473, 0) },
+	}
+	if _, err := verifier.Verify(r); err == nil {
+		t.Fatal("Expected a signature that doesn't cover the body to be rejected")
+	}
+}
+
+func TestVerifyRejectsUnsignedRequest(t *testing.T) {
+	jwks, _ := ed25519Jwks(t)
+	r := httptest.NewRequest("POST", "https://hooks.example.com/mattr", strings.NewReader(testBody))
+
+	if _, err := (Verifier{Keys: jwks}).Verify(r); err == nil {
+		t.Fatal("Expected an unsigned request to be rejected")
+	}
+}
+
+func TestParseJWKS(t *testing.T) {
+	jwks, err := ParseJWKS([]byte(`{"keys":[{"kid":"a","kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwk, err := jwks.Key("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := jwk.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := key.(crypto.PublicKey).(ed25519.PublicKey); !ok {
+		t.Fatalf("Expected an Ed25519 key, got %T", key)
+	}
+}